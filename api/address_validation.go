@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	addressValidationVersion = "v4"
+)
+
+// MaxAddressesToValidate is how many addresses FedEx validates per request
+const MaxAddressesToValidate = 100
+
+// ValidateAddresses validates addresses, returning a result per address in
+// the same order
+func (a API) ValidateAddresses(addresses []models.Address) ([]models.AddressValidationResult, error) {
+	return a.ValidateAddressesContext(context.Background(), addresses)
+}
+
+// ValidateAddressesContext is like ValidateAddresses but aborts the requests
+// when ctx is done
+func (a API) ValidateAddressesContext(ctx context.Context, addresses []models.Address) ([]models.AddressValidationResult, error) {
+	endpoint := fmt.Sprintf("/addressvalidation/%s", addressValidationVersion)
+	results := make([]models.AddressValidationResult, len(addresses))
+
+	for start := 0; start < len(addresses); start += MaxAddressesToValidate {
+		end := start + MaxAddressesToValidate
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		request := a.addressValidationRequest(addresses[start:end], start)
+		response := &models.AddressValidationResponseEnvelope{}
+		err := a.makeRequestAndUnmarshalResponse(ctx, "AddressValidation", endpoint, request, response)
+		if err != nil {
+			return nil, fmt.Errorf("make address validation request and unmarshal: %w", err)
+		}
+
+		for _, result := range response.Reply.AddressResults {
+			idx, err := strconv.Atoi(result.ClientReferenceID)
+			if err != nil || idx < start || idx >= end {
+				return nil, fmt.Errorf("unexpected address validation result %s", result.ClientReferenceID)
+			}
+			results[idx] = result
+		}
+	}
+	return results, nil
+}
+
+// addressValidationRequest returns the request validating addresses, whose
+// client reference IDs are their index from offset
+func (a API) addressValidationRequest(addresses []models.Address, offset int) *models.Envelope {
+	addressesToValidate := make([]models.AddressToValidate, len(addresses))
+	for idx, address := range addresses {
+		addressesToValidate[idx] = models.AddressToValidate{
+			ClientReferenceID: strconv.Itoa(offset + idx),
+			Address:           address,
+		}
+	}
+
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/addressvalidation/%s", addressValidationVersion),
+		Body: models.AddressValidationBody{
+			AddressValidationRequest: models.AddressValidationRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "aval",
+						Major:     4,
+					},
+				},
+				InEffectAsOfTimestamp: models.Timestamp(time.Now()),
+				AddressesToValidate:   addressesToValidate,
+			},
+		},
+	}
+}
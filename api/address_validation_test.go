@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestValidateAddresses(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+	server.SetAddressClassification("1106 Broadway", models.AddressClassificationBusiness)
+
+	addresses := []models.Address{fromAndTo.ToAddress, {CountryCode: "US"}}
+	results, err := a.ValidateAddresses(addresses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatal("should have a result per address, got", len(results))
+	}
+
+	if !results[0].Resolved() || results[0].State != models.AddressValidationStateStandardized ||
+		results[0].Classification != models.AddressClassificationBusiness {
+		t.Fatal("known address should be resolved as a business", results[0])
+	}
+	if address := results[0].Address(); address.City != "SANTA MONICA" || bool(address.Residential) {
+		t.Fatal("should have the standardized address", address)
+	}
+	if changed := results[0].ChangedFields(fromAndTo.ToAddress); len(changed) != 0 {
+		t.Fatal("only the case of the address changed, got", changed)
+	}
+	if results[1].Resolved() || results[1].Classification != models.AddressClassificationUnknown {
+		t.Fatal("empty address should not be resolved", results[1])
+	}
+
+}
@@ -1,5 +1,10 @@
 package api
 
+import (
+	"net/http"
+	"time"
+)
+
 type API struct {
 	Key      string `json:"key"`
 	Password string `json:"password"`
@@ -8,4 +13,44 @@ type API struct {
 	HubID    string `json:"hubID"` // for SmartPost
 
 	FedExURL string `json:"fedexURL"`
+
+	// HTTPClient posts requests to FedEx. http.DefaultClient is used when nil.
+	HTTPClient *http.Client `json:"-"`
+	// Transport, when set, replaces the transport of HTTPClient, e.g. to add
+	// tracing, mTLS or a fake for tests
+	Transport http.RoundTripper `json:"-"`
+	// Timeout bounds every call to FedEx, on top of any deadline on the
+	// caller's context. Zero means no timeout.
+	Timeout time.Duration `json:"-"`
+
+	// Logger receives logs of failed and retried requests. DefaultLogger is
+	// used when nil.
+	Logger Logger `json:"-"`
+
+	// RetryPolicy controls retries after transient failures.
+	// DefaultRetryPolicy is used when nil.
+	RetryPolicy *RetryPolicy `json:"-"`
+
+	// TrackConcurrency bounds the track requests TrackMany sends at once.
+	// DefaultTrackConcurrency is used when zero.
+	TrackConcurrency int `json:"-"`
+
+	// StrictWarningCodes are codes of WARNING and NOTE notifications that fail
+	// the call instead of being returned on the reply
+	StrictWarningCodes []string `json:"-"`
+}
+
+// httpClient returns the client requests are posted with
+func (a API) httpClient() *http.Client {
+	client := a.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if a.Transport == nil {
+		return client
+	}
+
+	withTransport := *client
+	withTransport.Transport = a.Transport
+	return &withTransport
 }
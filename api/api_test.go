@@ -0,0 +1,38 @@
+package api
+
+import (
+	"time"
+
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+var fromAndTo = models.FromAndTo{
+	FromAddress: models.Address{
+		StreetLines:         []string{"1517 Lincoln Blvd"},
+		City:                "Santa Monica",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	},
+	ToAddress: models.Address{
+		StreetLines:         []string{"1106 Broadway"},
+		City:                "Santa Monica",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	},
+	FromContact: models.Contact{PersonName: "Joe Customer", PhoneNumber: "2135550000"},
+	ToContact:   models.Contact{CompanyName: "Happy Returns", PhoneNumber: "4243259510"},
+}
+
+// newServer starts a fake server and returns a client of it. Close the server
+// when done.
+func newServer() (*fedextest.Server, API) {
+	server := fedextest.NewServer()
+	a := testAPI
+	a.FedExURL = server.URL
+	a.Logger = NopLogger{}
+	a.RetryPolicy = &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	return server, a
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// CancelPickup cancels the pickup of confirmationNumber scheduled on
+// scheduledDate. location is the FedEx location of the pickup, from
+// CreatePickupReply.Location.
+func (a API) CancelPickup(carrierCode, confirmationNumber string, scheduledDate time.Time, location string) error {
+	return a.CancelPickupContext(context.Background(), carrierCode, confirmationNumber, scheduledDate, location)
+}
+
+// CancelPickupContext is like CancelPickup but aborts the request when ctx is
+// done
+func (a API) CancelPickupContext(ctx context.Context, carrierCode, confirmationNumber string, scheduledDate time.Time, location string) error {
+	endpoint := fmt.Sprintf("/pickup/%s", createPickupVersion)
+	request := a.cancelPickupRequest(carrierCode, confirmationNumber, scheduledDate, location)
+	response := &models.CancelPickupResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "CancelPickup", endpoint, request, response); err != nil {
+		return fmt.Errorf("make cancel pickup request and unmarshal: %w", err)
+	}
+	return nil
+}
+
+func (a API) cancelPickupRequest(carrierCode, confirmationNumber string, scheduledDate time.Time, location string) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/pickup/%s", createPickupVersion),
+		Body: models.CancelPickupBody{
+			CancelPickupRequest: models.CancelPickupRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "disp",
+						Major:     17,
+					},
+				},
+				CarrierCode:              carrierCode,
+				PickupConfirmationNumber: confirmationNumber,
+				ScheduledDate:            scheduledDate.Format("2006-01-02"),
+				Location:                 location,
+			},
+		},
+	}
+}
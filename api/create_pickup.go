@@ -1,8 +1,9 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/happyreturns/fedex/models"
 )
@@ -12,20 +13,26 @@ const (
 )
 
 func (a API) CreatePickup(pickup *models.Pickup, window *models.PickupTimeWindow) (*models.CreatePickupReply, error) {
+	return a.CreatePickupContext(context.Background(), pickup, window)
+}
+
+// CreatePickupContext is like CreatePickup but aborts the request when ctx is
+// done
+func (a API) CreatePickupContext(ctx context.Context, pickup *models.Pickup, window *models.PickupTimeWindow) (*models.CreatePickupReply, error) {
 	request, err := a.createPickupRequest(pickup, window)
 	if err != nil {
-		return nil, fmt.Errorf("create pickup request: %s", err)
+		return nil, fmt.Errorf("create pickup request: %w", err)
 	}
 
 	endpoint := fmt.Sprintf("/pickup/%s", createPickupVersion)
 	response := &models.CreatePickupResponseEnvelope{}
-	err = a.makeRequestAndUnmarshalResponse(endpoint, request, response)
+	err = a.makeRequestAndUnmarshalResponse(ctx, "CreatePickup", endpoint, request, response)
 
 	switch {
-	case err != nil && strings.Contains(err.Error(), "pickup already exists"):
+	case errors.Is(err, models.ErrPickupAlreadyExists):
 		return nil, models.PickupAlreadyExistsError{}
 	case err != nil:
-		return nil, fmt.Errorf("make create pickup request and unmarshal: %s", err)
+		return nil, fmt.Errorf("make create pickup request and unmarshal: %w", err)
 	default:
 		return &response.Reply, nil
 	}
@@ -85,7 +92,7 @@ func (a API) createPickupRequest(pickup *models.Pickup, window *models.PickupTim
 					},
 				},
 				PackageCount:         1,
-				CarrierCode:          models.CarrierCodeFDXG,
+				CarrierCode:          pickup.Carrier(),
 				Remarks:              "",
 				CommodityDescription: "",
 			},
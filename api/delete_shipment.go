@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// DeleteShipment cancels the shipment of trackingNumber so it isn't billed.
+// deletionControl is models.DeletionControlDeleteOnePackage to only cancel
+// that package of a multi-piece shipment, or
+// models.DeletionControlDeleteAllPackages. It returns
+// models.ShipmentAlreadyTenderedError once FedEx has the package.
+func (a API) DeleteShipment(trackingNumber, deletionControl string) error {
+	return a.DeleteShipmentContext(context.Background(), trackingNumber, deletionControl)
+}
+
+// DeleteShipmentContext is like DeleteShipment but aborts the request when ctx
+// is done
+func (a API) DeleteShipmentContext(ctx context.Context, trackingNumber, deletionControl string) error {
+	return a.DeleteShipmentByTrackingIDContext(ctx, models.TrackingID{TrackingNumber: trackingNumber}, deletionControl)
+}
+
+// DeleteShipmentByTrackingID is like DeleteShipment, for a TrackingID of
+// CompletedPackageDetails.TrackingIds
+func (a API) DeleteShipmentByTrackingID(trackingID models.TrackingID, deletionControl string) error {
+	return a.DeleteShipmentByTrackingIDContext(context.Background(), trackingID, deletionControl)
+}
+
+// DeleteShipmentByTrackingIDContext is like DeleteShipmentByTrackingID but
+// aborts the request when ctx is done
+func (a API) DeleteShipmentByTrackingIDContext(ctx context.Context, trackingID models.TrackingID, deletionControl string) error {
+	endpoint := fmt.Sprintf("/ship/%s", processShipmentVersion)
+	request := a.deleteShipmentRequest(trackingID, deletionControl)
+	response := &models.DeleteShipmentResponseEnvelope{}
+	err := a.makeRequestAndUnmarshalResponse(ctx, "DeleteShipment", endpoint, request, response)
+
+	switch {
+	case errors.Is(err, models.ErrShipmentAlreadyTendered):
+		return models.ShipmentAlreadyTenderedError{}
+	case err != nil:
+		return fmt.Errorf("make delete shipment request and unmarshal: %w", err)
+	default:
+		return nil
+	}
+}
+
+func (a API) deleteShipmentRequest(trackingID models.TrackingID, deletionControl string) *models.Envelope {
+	// FedEx requires the tracking ID type, which isn't known when deleting by
+	// tracking number
+	requestedTrackingID := trackingID.Requested()
+	if requestedTrackingID.TrackingIDType == "" {
+		requestedTrackingID.TrackingIDType = models.TrackingIDTypeFedex
+	}
+
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/ship/%s", processShipmentVersion),
+		Body: models.DeleteShipmentBody{
+			DeleteShipmentRequest: models.DeleteShipmentRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "ship",
+						Major:     23,
+					},
+				},
+				TrackingID:      *requestedTrackingID,
+				DeletionControl: deletionControl,
+			},
+		},
+	}
+}
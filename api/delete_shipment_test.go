@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestDeleteShipmentRequest(t *testing.T) {
+	tests := []struct {
+		trackingID     models.TrackingID
+		trackingIDType string
+	}{
+		{models.TrackingID{TrackingNumber: "794000000001"}, "FEDEX"},
+		{models.TrackingID{TrackingIdType: "GROUND", TrackingNumber: "794000000001"}, "GROUND"},
+	}
+	for _, test := range tests {
+		envelope := testAPI.deleteShipmentRequest(test.trackingID, models.DeletionControlDeleteOnePackage)
+		body, err := xml.Marshal(envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "<q0:TrackingId><q0:TrackingIdType>" + test.trackingIDType + "</q0:TrackingIdType><q0:TrackingNumber>794000000001</q0:TrackingNumber></q0:TrackingId>"
+		if !strings.Contains(string(body), expected) {
+			t.Fatal("tracking ID doesn't match", string(body))
+		}
+	}
+}
+
+func TestDeleteShipment(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	reply, err := a.ProcessShipment(&models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground", Packages: []models.PackageDetail{{}, {}, {}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trackingNumbers := reply.TrackingNumbers()
+
+	if err := a.DeleteShipment(trackingNumbers[2], models.DeletionControlDeleteOnePackage); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.DeleteShipment(trackingNumbers[2], models.DeletionControlDeleteOnePackage); err == nil {
+		t.Fatal("deleting a package twice should fail")
+	}
+	trackReply, err := a.TrackByNumber(models.CarrierCodeFDXG, trackingNumbers[1])
+	if err != nil || trackReply.PrimaryTrackDetail().Status() != models.TrackingStatusLabelCreated {
+		t.Fatal("other packages should not be deleted", err)
+	}
+
+	server.SetTracking(trackingNumbers[1], fedextest.Tracking{
+		CarrierCode:          "FDXG",
+		MasterTrackingNumber: trackingNumbers[0],
+		Events:               []fedextest.TrackingEvent{{Timestamp: time.Now(), EventType: "PU"}},
+	})
+	err = a.DeleteShipmentByTrackingID(reply.CompletedShipmentDetail.MasterTrackingId, models.DeletionControlDeleteAllPackages)
+	if !errors.Is(err, models.ErrShipmentAlreadyTendered) {
+		t.Fatal("shipments picked up should not be deleted", err)
+	}
+
+	server.SetTracking(trackingNumbers[1], fedextest.Tracking{
+		CarrierCode:          "FDXG",
+		MasterTrackingNumber: trackingNumbers[0],
+		Events:               []fedextest.TrackingEvent{{Timestamp: time.Now(), EventType: "OC"}},
+	})
+	if err := a.DeleteShipment(trackingNumbers[1], models.DeletionControlDeleteAllPackages); err != nil {
+		t.Fatal(err)
+	}
+	for _, trackingNumber := range trackingNumbers[:2] {
+		trackReply, err := a.TrackByNumber(models.CarrierCodeFDXG, trackingNumber)
+		if err != nil || trackReply.PrimaryTrackDetail().Status() != models.TrackingStatusCancelled {
+			t.Fatal("every package of the shipment should be deleted", err)
+		}
+	}
+}
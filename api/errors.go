@@ -0,0 +1,81 @@
+package api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// maxErrorBodyLength is how much of a failed response TransportError keeps
+const maxErrorBodyLength = 512
+
+// TransportError is returned when FedEx responds with a non-2xx status or a
+// SOAP Fault instead of a reply
+type TransportError struct {
+	StatusCode int
+	// Body is the start of the response body, truncated to
+	// maxErrorBodyLength bytes
+	Body string
+
+	// Set when the body is a SOAP Fault
+	FaultCode   string
+	FaultString string
+	FaultDetail string
+}
+
+func (t *TransportError) Error() string {
+	if t.IsFault() {
+		return fmt.Sprintf("http status %d: soap fault %s: %s", t.StatusCode, t.FaultCode, t.FaultString)
+	}
+	return fmt.Sprintf("http status %d: %s", t.StatusCode, t.Body)
+}
+
+// IsFault reports whether the response was a SOAP Fault
+func (t *TransportError) IsFault() bool {
+	return t.FaultCode != "" || t.FaultString != ""
+}
+
+type soapFaultEnvelope struct {
+	Fault *struct {
+		FaultCode   string `xml:"faultcode"`
+		FaultString string `xml:"faultstring"`
+		Detail      struct {
+			InnerXML string `xml:",innerxml"`
+		} `xml:"detail"`
+	} `xml:"Body>Fault"`
+}
+
+// transportError returns a *TransportError if the response isn't a FedEx
+// reply, or nil if it is
+func transportError(statusCode int, content []byte) error {
+	isSuccess := statusCode >= 200 && statusCode < 300
+
+	// Only look for a fault when the body could have one, successful replies
+	// with labels can be megabytes long
+	envelope := soapFaultEnvelope{}
+	if bytes.Contains(content, []byte("Fault>")) {
+		xml.Unmarshal(content, &envelope)
+	}
+	if isSuccess && envelope.Fault == nil {
+		return nil
+	}
+
+	transportErr := &TransportError{
+		StatusCode: statusCode,
+		Body:       truncate(string(content), maxErrorBodyLength),
+	}
+	if envelope.Fault != nil {
+		transportErr.FaultCode = envelope.Fault.FaultCode
+		transportErr.FaultString = envelope.Fault.FaultString
+		transportErr.FaultDetail = truncate(strings.TrimSpace(envelope.Fault.Detail.InnerXML), maxErrorBodyLength)
+	}
+	return transportErr
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
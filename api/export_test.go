@@ -0,0 +1,12 @@
+package api
+
+// Exported for the round trip tests in package api_test, which can't be in
+// package api since they import the cassette package
+var TestAPI = testAPI
+
+// Exported so the round trip tests ship the same shipments as the request
+// tests
+var (
+	GroundShipment        = groundShipment
+	InternationalShipment = internationalShipment
+)
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// defaultPickupAvailabilityBusinessDays is how many business days after the
+// dispatch date availability is asked for when not given
+const defaultPickupAvailabilityBusinessDays = 5
+
+// PickupAvailabilityOptions are the optional parameters of
+// GetPickupAvailabilityWithOptions
+type PickupAvailabilityOptions struct {
+	// BusinessDays is how many business days after the dispatch date
+	// availability is asked for, five when zero
+	BusinessDays int
+}
+
+// GetPickupAvailability returns when carrierCode can pick up at address, on
+// dispatchDate and the following five business days, with the cut off and
+// access times of each day
+func (a API) GetPickupAvailability(address models.Address, carrierCode string, dispatchDate time.Time) (*models.GetPickupAvailabilityReply, error) {
+	return a.GetPickupAvailabilityContext(context.Background(), address, carrierCode, dispatchDate)
+}
+
+// GetPickupAvailabilityContext is like GetPickupAvailability but aborts the
+// request when ctx is done
+func (a API) GetPickupAvailabilityContext(ctx context.Context, address models.Address, carrierCode string, dispatchDate time.Time) (*models.GetPickupAvailabilityReply, error) {
+	return a.GetPickupAvailabilityWithOptionsContext(ctx, address, carrierCode, dispatchDate, PickupAvailabilityOptions{})
+}
+
+// GetPickupAvailabilityWithOptions is like GetPickupAvailability, with options
+func (a API) GetPickupAvailabilityWithOptions(address models.Address, carrierCode string, dispatchDate time.Time, options PickupAvailabilityOptions) (*models.GetPickupAvailabilityReply, error) {
+	return a.GetPickupAvailabilityWithOptionsContext(context.Background(), address, carrierCode, dispatchDate, options)
+}
+
+// GetPickupAvailabilityWithOptionsContext is like
+// GetPickupAvailabilityWithOptions but aborts the request when ctx is done
+func (a API) GetPickupAvailabilityWithOptionsContext(ctx context.Context, address models.Address, carrierCode string, dispatchDate time.Time, options PickupAvailabilityOptions) (*models.GetPickupAvailabilityReply, error) {
+	businessDays := options.BusinessDays
+	if businessDays <= 0 {
+		businessDays = defaultPickupAvailabilityBusinessDays
+	}
+
+	endpoint := fmt.Sprintf("/pickup/%s", createPickupVersion)
+	request := a.getPickupAvailabilityRequest(address, carrierCode, dispatchDate, businessDays)
+	response := &models.GetPickupAvailabilityResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "GetPickupAvailability", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make get pickup availability request and unmarshal: %w", err)
+	}
+	return &response.Reply, nil
+}
+
+func (a API) getPickupAvailabilityRequest(address models.Address, carrierCode string, dispatchDate time.Time, businessDays int) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/pickup/%s", createPickupVersion),
+		Body: models.GetPickupAvailabilityBody{
+			GetPickupAvailabilityRequest: models.GetPickupAvailabilityRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "disp",
+						Major:     17,
+					},
+				},
+				PickupAddress:        address,
+				PickupRequestType:    []string{models.PickupRequestTypeSameDay, models.PickupRequestTypeFutureDay},
+				DispatchDate:         dispatchDate.Format("2006-01-02"),
+				NumberOfBusinessDays: businessDays,
+				Carriers:             []string{carrierCode},
+			},
+		},
+	}
+}
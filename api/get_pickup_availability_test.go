@@ -0,0 +1,36 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestGetPickupAvailability(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	dispatchDate := time.Now().AddDate(0, 0, 1)
+	for dispatchDate.Weekday() != time.Saturday {
+		dispatchDate = dispatchDate.AddDate(0, 0, 1)
+	}
+	monday := dispatchDate.AddDate(0, 0, 2)
+
+	reply, err := a.GetPickupAvailability(fromAndTo.FromAddress, models.CarrierCodeFDXG, dispatchDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := reply.AvailableOptions()
+	if len(options) == 0 || options[0].PickupDate != monday.Format("2006-01-02") {
+		t.Fatal("weekends should not be available", options)
+	}
+	cutOff, err := options[0].CutOff(time.UTC)
+	if err != nil || cutOff.Hour() != 15 || cutOff.Day() != monday.Day() {
+		t.Fatal("should have the cut off time of the day", cutOff, err)
+	}
+	if accessTime, err := options[0].AccessDuration(); err != nil || accessTime != 2*time.Hour {
+		t.Fatal("should have the access time", accessTime, err)
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	trackingDocumentsVersion = "v16"
+)
+
+// GetSignatureProofOfDelivery gets the signature proof of delivery letter of
+// a delivered package, as imageType, PDF or PNG. It returns the decoded
+// letter and its image type.
+func (a API) GetSignatureProofOfDelivery(carrierCode, trackingNo, imageType string) ([]byte, string, error) {
+	return a.GetSignatureProofOfDeliveryContext(context.Background(), carrierCode, trackingNo, imageType)
+}
+
+// GetSignatureProofOfDeliveryContext is like GetSignatureProofOfDelivery but
+// aborts the request when ctx is done
+func (a API) GetSignatureProofOfDeliveryContext(ctx context.Context, carrierCode, trackingNo, imageType string) ([]byte, string, error) {
+	endpoint := fmt.Sprintf("/track/%s", trackingDocumentsVersion)
+	request := a.getSignatureProofOfDeliveryRequest(carrierCode, trackingNo, imageType)
+	response := &models.GetTrackingDocumentsResponseEnvelope{}
+
+	err := a.makeRequestAndUnmarshalResponse(ctx, "GetTrackingDocuments", endpoint, request, response)
+	if err != nil {
+		return nil, "", fmt.Errorf("make get tracking documents request and unmarshal: %w", err)
+	}
+	return response.Reply.SignatureProofOfDeliveryDataAndImageType()
+}
+
+func (a API) getSignatureProofOfDeliveryRequest(carrierCode, trackingNo, imageType string) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/track/%s", trackingDocumentsVersion),
+		Body: models.GetTrackingDocumentsBody{
+			GetTrackingDocumentsRequest: models.GetTrackingDocumentsRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "trck",
+						Major:     16,
+					},
+				},
+				SelectionDetails: []models.SelectionDetails{trackingNumberSelection(carrierCode, trackingNo)},
+				TrackingDocumentSpecification: models.TrackingDocumentSpecification{
+					DocumentTypes: []string{models.DocumentTypeSignatureProofOfDelivery},
+					SignatureProofOfDeliveryLetterDetail: &models.SignatureProofOfDeliveryLetterDetail{
+						DocumentFormat: models.TrackingDocumentFormat{ImageType: imageType},
+					},
+				},
+			},
+		},
+	}
+}
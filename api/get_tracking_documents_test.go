@@ -0,0 +1,33 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestSignatureProofOfDelivery(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	server.SetTracking("794000000060", fedextest.Tracking{CarrierCode: models.CarrierCodeFDXG})
+	if _, _, err := a.GetSignatureProofOfDelivery(models.CarrierCodeFDXG, "794000000060", models.ImageTypePDF); err == nil {
+		t.Fatal("packages not delivered should not have a signature proof of delivery")
+	}
+
+	server.SetTracking("794000000060", fedextest.Tracking{
+		CarrierCode:           models.CarrierCodeFDXG,
+		ActualDelivery:        time.Now(),
+		DeliverySignatureName: "J.DOE",
+	})
+	spod, imageType, err := a.GetSignatureProofOfDelivery(models.CarrierCodeFDXG, "794000000060", models.ImageTypePDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imageType != models.ImageTypePDF || !strings.HasPrefix(string(spod), "%PDF") {
+		t.Fatal("should be a decoded pdf", imageType, string(spod))
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	closeVersion = "v5"
+)
+
+// GroundClose closes the ground shipments of the account shipped up to
+// closeTime, returning the decoded manifest and reports. It fails with
+// ErrNothingToClose when there are no shipments to close.
+func (a API) GroundClose(closeTime time.Time) ([]models.CloseReport, error) {
+	return a.GroundCloseContext(context.Background(), closeTime)
+}
+
+// GroundCloseContext is like GroundClose but aborts the request when ctx is
+// done
+func (a API) GroundCloseContext(ctx context.Context, closeTime time.Time) ([]models.CloseReport, error) {
+	endpoint := fmt.Sprintf("/close/%s", closeVersion)
+	request := a.groundCloseRequest(closeTime)
+	response := &models.GroundCloseResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "GroundClose", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make ground close request and unmarshal: %w", err)
+	}
+
+	reports, err := response.Reply.Reports()
+	if err != nil {
+		return nil, fmt.Errorf("ground close reports: %w", err)
+	}
+	return reports, nil
+}
+
+// GroundCloseWithDocuments closes the ground shipments of the account shipped
+// on closeDate, returning the decoded documents of documentTypes, which are
+// CloseDocumentType constants
+func (a API) GroundCloseWithDocuments(closeDate time.Time, documentTypes []string) ([]models.CloseReport, error) {
+	return a.GroundCloseWithDocumentsContext(context.Background(), closeDate, documentTypes)
+}
+
+// GroundCloseWithDocumentsContext is like GroundCloseWithDocuments but aborts
+// the request when ctx is done
+func (a API) GroundCloseWithDocumentsContext(ctx context.Context, closeDate time.Time, documentTypes []string) ([]models.CloseReport, error) {
+	endpoint := fmt.Sprintf("/close/%s", closeVersion)
+	request := a.groundCloseWithDocumentsRequest(closeDate, documentTypes)
+	response := &models.GroundCloseDocumentsResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "GroundCloseWithDocuments", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make ground close with documents request and unmarshal: %w", err)
+	}
+
+	reports, err := response.Reply.Reports()
+	if err != nil {
+		return nil, fmt.Errorf("ground close documents: %w", err)
+	}
+	return reports, nil
+}
+
+func (a API) groundCloseRequest(closeTime time.Time) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/close/%s", closeVersion),
+		Body: models.GroundCloseBody{
+			GroundCloseRequest: models.GroundCloseRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "clos",
+						Major:     5,
+					},
+				},
+				TimeUpToWhichShipmentsAreToBeClosed: models.Timestamp(closeTime),
+			},
+		},
+	}
+}
+
+func (a API) groundCloseWithDocumentsRequest(closeDate time.Time, documentTypes []string) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/close/%s", closeVersion),
+		Body: models.GroundCloseWithDocumentsBody{
+			GroundCloseWithDocumentsRequest: models.GroundCloseWithDocumentsRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "clos",
+						Major:     5,
+					},
+				},
+				CloseDate: closeDate.Format("2006-01-02"),
+				CloseDocumentSpecification: models.CloseDocumentSpecification{
+					CloseDocumentTypes: documentTypes,
+				},
+			},
+		},
+	}
+}
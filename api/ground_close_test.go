@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestGroundClose(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	trackingNumbers := []string{}
+	for i := 0; i < 2; i++ {
+		reply, err := a.ProcessShipment(&models.Shipment{FromAndTo: fromAndTo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		trackingNumbers = append(trackingNumbers, reply.TrackingNumbers()...)
+	}
+
+	reports, err := a.GroundClose(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Type != models.CloseDocumentTypeManifest || reports[0].FileName == "" {
+		t.Fatal("should have the manifest", reports)
+	}
+	for _, trackingNumber := range trackingNumbers {
+		if !strings.Contains(string(reports[0].Data), trackingNumber) {
+			t.Fatal("manifest should have every package", string(reports[0].Data))
+		}
+	}
+	if _, err := a.GroundClose(time.Now()); !errors.Is(err, models.ErrNothingToClose) {
+		t.Fatal("packages should only be closed once", err)
+	}
+
+	if _, err := a.ProcessShipment(&models.Shipment{FromAndTo: fromAndTo}); err != nil {
+		t.Fatal(err)
+	}
+	documentTypes := []string{models.CloseDocumentTypeManifest, models.CloseDocumentTypeMultiweightReport}
+	reports, err = a.GroundCloseWithDocuments(time.Now(), documentTypes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[1].Type != models.CloseDocumentTypeMultiweightReport || len(reports[1].Data) == 0 {
+		t.Fatal("should have the requested documents", reports)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields are structured data attached to a log entry
+type Fields map[string]interface{}
+
+// Logger receives the logs of the library. Requests, responses and errors
+// are redacted with RedactXML before being logged.
+type Logger interface {
+	Info(msg string, fields Fields)
+	Error(msg string, fields Fields)
+}
+
+// DefaultLogger is used when API.Logger is nil. It logs through the standard
+// logrus logger, without changing its configuration.
+var DefaultLogger = NewLogrusLogger(logrus.WithField("app", "fedex"))
+
+// NewLogrusLogger returns a Logger that logs to entry
+func NewLogrusLogger(entry *logrus.Entry) Logger {
+	return logrusLogger{entry: entry}
+}
+
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+func (l logrusLogger) Info(msg string, fields Fields) {
+	l.entry.WithFields(logrus.Fields(fields)).Info(msg)
+}
+
+func (l logrusLogger) Error(msg string, fields Fields) {
+	l.entry.WithFields(logrus.Fields(fields)).Error(msg)
+}
+
+// NopLogger discards all logs
+type NopLogger struct{}
+
+func (NopLogger) Info(msg string, fields Fields)  {}
+func (NopLogger) Error(msg string, fields Fields) {}
+
+// EffectiveLogger returns Logger, or DefaultLogger when it's nil
+func (a API) EffectiveLogger() Logger {
+	if a.Logger == nil {
+		return DefaultLogger
+	}
+	return a.Logger
+}
+
+// redactedElements are elements with credentials or PII, whose content is
+// masked by RedactXML
+var redactedElements = []string{
+	// Credentials
+	"Key",
+	"Password",
+	"AccountNumber",
+	"MeterNumber",
+	"ShipmentAccountNumber",
+
+	// Contacts
+	"PersonName",
+	"PhoneNumber",
+	"PhoneExtension",
+	"FaxNumber",
+	"EMailAddress",
+	"EmailAddress",
+	"SenderEMailAddress",
+	"SenderContactName",
+	"DeliverySignatureName",
+
+	// Addresses
+	"StreetLines",
+}
+
+var redactRegex = regexp.MustCompile(`<((?:[\w-]+:)?(?:` + strings.Join(redactedElements, "|") + `))(\s[^>]*)?>([^<]*)</`)
+
+// RedactXML masks credentials, account and meter numbers, and contact PII in
+// a FedEx request or response
+func RedactXML(s string) string {
+	return redactRegex.ReplaceAllString(s, "<$1$2>***</")
+}
+
+// minRedactedValueLength is the shortest value of a redacted element that
+// redactError masks, so that short values don't mask unrelated text
+const minRedactedValueLength = 3
+
+var xmlUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#34;", `"`, "&apos;", "'", "&#39;", "'", "&amp;", "&")
+
+// redactError returns the text of err redacted like RedactXML, and with the
+// values of the redacted elements of reqXML masked, since FedEx echoes them
+// back in error messages
+func redactError(err error, reqXML string) string {
+	text := RedactXML(err.Error())
+	for _, match := range redactRegex.FindAllStringSubmatch(reqXML, -1) {
+		value := strings.TrimSpace(xmlUnescaper.Replace(match[3]))
+		if len(value) >= minRedactedValueLength {
+			text = strings.ReplaceAll(text, value, "***")
+		}
+	}
+	return text
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestRedactXML(t *testing.T) {
+	shipment := &models.Shipment{
+		FromAndTo: models.FromAndTo{
+			FromAddress: models.Address{
+				StreetLines:         []string{"1511 15th Street", "Apt 2"},
+				City:                "Santa Monica",
+				StateOrProvinceCode: "CA",
+				PostalCode:          "90404",
+				CountryCode:         "US",
+			},
+			FromContact: models.Contact{
+				PersonName:   "Joe Customer",
+				PhoneNumber:  "2045551234",
+				EmailAddress: "joe@customer.com",
+			},
+		},
+	}
+	envelope, err := testAPI.processShipmentRequest(shipment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqXML, err := xml.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := RedactXML(string(reqXML))
+	for _, secret := range []string{"Key<", "Password<", ">Account<", ">Meter<", "1511 15th Street", "Apt 2", "Joe Customer", "2045551234", "joe@customer.com"} {
+		if strings.Contains(redacted, secret) {
+			t.Fatal("should have redacted", secret)
+		}
+	}
+	for _, kept := range []string{"<q0:Key>***</q0:Key>", "<q0:City>Santa Monica</q0:City>", "<q0:PostalCode>90404</q0:PostalCode>"} {
+		if !strings.Contains(redacted, kept) {
+			t.Fatal("should have kept", kept)
+		}
+	}
+}
+
+type recordingLogger struct {
+	messages []string
+	fields   []Fields
+}
+
+func (r *recordingLogger) Info(msg string, fields Fields) {
+	r.messages = append(r.messages, msg)
+	r.fields = append(r.fields, fields)
+}
+
+func (r *recordingLogger) Error(msg string, fields Fields) {
+	r.Info(msg, fields)
+}
+
+func TestLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	a := testAPI
+	a.FedExURL = "http://127.0.0.1:0"
+	a.Logger = logger
+	a.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+
+	if _, err := a.TrackByNumber("FDXG", "123456789012"); err == nil {
+		t.Fatal("should fail to post")
+	}
+	if len(logger.messages) != 1 || logger.messages[0] != "error-posting-xml" {
+		t.Fatal("should have logged the failed post", logger.messages)
+	}
+	if request := logger.fields[0]["request"].(string); strings.Contains(request, "Password<") {
+		t.Fatal("should have redacted the request", request)
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	reqXML := `<q0:Contact><q0:PersonName>Joe &amp; Jane Customer</q0:PersonName></q0:Contact>` +
+		`<q0:Address><q0:StreetLines>1511 15th Street</q0:StreetLines><q0:City>Santa Monica</q0:City></q0:Address>`
+	errs := []error{
+		&TransportError{StatusCode: 500, Body: `<Fault><PersonName>Joe Customer</PersonName></Fault>`},
+		&models.ReplyError{HighestSeverity: "ERROR", Notifications: []models.Notification{{
+			Severity: "ERROR",
+			Message:  "Recipient Joe & Jane Customer at 1511 15th Street is invalid",
+		}}},
+	}
+
+	for _, err := range errs {
+		redacted := redactError(fmt.Errorf("post xml: %w", err), reqXML)
+		for _, secret := range []string{"Joe", "1511 15th Street"} {
+			if strings.Contains(redacted, secret) {
+				t.Fatal("should have redacted", secret, redacted)
+			}
+		}
+	}
+}
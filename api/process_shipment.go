@@ -1,37 +1,98 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/happyreturns/fedex/models"
 )
 
 const (
 	processShipmentVersion = "v23"
+	// rollbackTimeout bounds deleting the packages shipped before a child
+	// package failed
+	rollbackTimeout = 30 * time.Second
 )
 
 func (a API) ProcessShipment(shipment *models.Shipment) (*models.ProcessShipmentReply, error) {
-	request, err := a.processShipmentRequest(shipment)
+	return a.ProcessShipmentContext(context.Background(), shipment)
+}
+
+// ProcessShipmentContext is like ProcessShipment but aborts the request when
+// ctx is done.
+//
+// A shipment with several packages is shipped as a multi-piece shipment: the
+// master package first, then each child package with the master tracking ID.
+// The replies of the child packages are in the ChildReplies of the reply.
+//
+// When a child package fails, the packages already shipped are deleted, even
+// if ctx is done, so they aren't billed. If that fails too, the reply of the
+// packages shipped so far is returned with the error, so they can be deleted
+// later.
+func (a API) ProcessShipmentContext(ctx context.Context, shipment *models.Shipment) (*models.ProcessShipmentReply, error) {
+	items := shipment.RequestedPackageLineItems()
+
+	reply, err := a.processPackage(ctx, shipment, len(items), items[0], nil)
 	if err != nil {
-		return nil, fmt.Errorf("create process shipment request: %s", err)
+		return nil, err
+	}
+
+	masterTrackingID := reply.CompletedShipmentDetail.MasterTrackingId
+	for _, item := range items[1:] {
+		childReply, err := a.processPackage(ctx, shipment, len(items), item, masterTrackingID.Requested())
+		if err != nil {
+			err = fmt.Errorf("package %d of master %s: %w", item.SequenceNumber, masterTrackingID.TrackingNumber, err)
+			if deleteErr := a.rollback(masterTrackingID); deleteErr != nil {
+				return reply, fmt.Errorf("%w, and deleting the shipped packages failed: %s", err, deleteErr)
+			}
+			return nil, err
+		}
+		reply.ChildReplies = append(reply.ChildReplies, *childReply)
+	}
+
+	return reply, nil
+}
+
+// rollback deletes the packages of the multi-piece shipment of
+// masterTrackingID. It doesn't use the context of the shipment, which may be
+// why a child package failed.
+func (a API) rollback(masterTrackingID models.TrackingID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rollbackTimeout)
+	defer cancel()
+	return a.DeleteShipmentByTrackingIDContext(ctx, masterTrackingID, models.DeletionControlDeleteAllPackages)
+}
+
+// processPackage ships one package of shipment, which is a child package when
+// masterTrackingID is set
+func (a API) processPackage(ctx context.Context, shipment *models.Shipment, packageCount int, item models.RequestedPackageLineItem, masterTrackingID *models.RequestedTrackingID) (*models.ProcessShipmentReply, error) {
+	request, err := a.processPackageRequest(shipment, packageCount, item, masterTrackingID)
+	if err != nil {
+		return nil, fmt.Errorf("create process shipment request: %w", err)
 	}
 
 	endpoint := fmt.Sprintf("/ship/%s", processShipmentVersion)
 	response := &models.ShipResponseEnvelope{}
-	if err := a.makeRequestAndUnmarshalResponse(endpoint, request, response); err != nil {
-		return nil, fmt.Errorf("make process shipment request and unmarshal: %s", err)
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "ProcessShipment", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make process shipment request and unmarshal: %w", err)
 	}
 
 	return &response.Reply, nil
 }
 
+// processShipmentRequest returns the request shipping the master package of
+// shipment
 func (a API) processShipmentRequest(shipment *models.Shipment) (*models.Envelope, error) {
+	items := shipment.RequestedPackageLineItems()
+	return a.processPackageRequest(shipment, len(items), items[0], nil)
+}
+
+func (a API) processPackageRequest(shipment *models.Shipment, packageCount int, item models.RequestedPackageLineItem, masterTrackingID *models.RequestedTrackingID) (*models.Envelope, error) {
 	customsClearanceDetail, err := a.customsClearanceDetail(shipment)
 	if err != nil {
-		return nil, fmt.Errorf("customs clearance detail: %s", err)
+		return nil, fmt.Errorf("customs clearance detail: %w", err)
 	}
 
-	packageCount := 1
 	serviceType := shipment.ServiceType()
 
 	return &models.Envelope{
@@ -83,8 +144,9 @@ func (a API) processShipmentRequest(shipment *models.Shipment) (*models.Envelope
 					CustomsClearanceDetail:        customsClearanceDetail,
 					LabelSpecification:            shipment.LabelSpecification(),
 					ShippingDocumentSpecification: shipment.ShippingDocumentSpecification(),
+					MasterTrackingID:              masterTrackingID,
 					PackageCount:                  &packageCount,
-					RequestedPackageLineItems:     shipment.RequestedPackageLineItems(),
+					RequestedPackageLineItems:     []models.RequestedPackageLineItem{item},
 				},
 			},
 		},
@@ -109,7 +171,7 @@ func (a API) customsClearanceDetail(shipment *models.Shipment) (*models.CustomsC
 
 	customsValue, err := shipment.Commodities.CustomsValue()
 	if err != nil {
-		return nil, fmt.Errorf("commodities customs value: %s", err)
+		return nil, fmt.Errorf("commodities customs value: %w", err)
 	}
 
 	importerOfRecord := models.Shipper{
@@ -0,0 +1,109 @@
+package api_test
+
+import (
+	"flag"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/happyreturns/fedex/api"
+	"github.com/happyreturns/fedex/cassette"
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+var record = flag.Bool("record", false, "record cassettes against fedextest instead of replaying them")
+
+// cassetteAPI returns an API replaying the named cassette, or recording it
+// with -record. Call done at the end of the test. The cassettes are
+// synthetic, recorded against fedextest rather than the FedEx beta.
+func cassetteAPI(t *testing.T, name string) (a api.API, done func()) {
+	path := filepath.Join("testdata", name+".json")
+	a = api.TestAPI
+	a.FedExURL = "https://wsbeta.fedex.com:443/web-services"
+	a.Logger = api.NopLogger{}
+
+	if !*record {
+		c, err := cassette.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.Transport = c
+		return a, func() {}
+	}
+
+	server := fedextest.NewServer()
+	c := cassette.New(path)
+	c.Transport = toServer{url: server.URL}
+	a.Transport = c
+	return a, func() {
+		server.Close()
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// toServer sends requests to the server at url instead of FedEx
+type toServer struct {
+	url string
+}
+
+func (s toServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	serverURL, err := url.Parse(s.url)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = serverURL.Scheme
+	req.URL.Host = serverURL.Host
+	req.URL.Path = strings.TrimPrefix(req.URL.Path, "/web-services")
+	req.Host = ""
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestGroundShipmentRoundTrip(t *testing.T) {
+	a, done := cassetteAPI(t, "ground_shipment")
+	defer done()
+
+	reply, err := a.ProcessShipment(api.GroundShipment())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.CompletedShipmentDetail.CarrierCode != "FDXG" ||
+		len(reply.CompletedShipmentDetail.CompletedPackageDetails.TrackingIds) != 1 ||
+		reply.CompletedShipmentDetail.CompletedPackageDetails.TrackingIds[0].TrackingNumber == "" {
+		t.Fatal("shipment doesn't match")
+	}
+	if _, imageType, err := reply.LabelDataAndImageType(); err != nil || imageType != models.ImageTypePNG {
+		t.Fatal("should have a png label", err)
+	}
+	if _, _, err := reply.CommercialInvoiceDataAndImageType(); err == nil {
+		t.Fatal("domestic shipment should not have a commercial invoice")
+	}
+}
+
+func TestInternationalShipmentRoundTrip(t *testing.T) {
+	a, done := cassetteAPI(t, "international_shipment")
+	defer done()
+
+	reply, err := a.ProcessShipment(api.InternationalShipment())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, imageType, err := reply.LabelDataAndImageType(); err != nil || imageType != models.ImageTypePDF {
+		t.Fatal("should have a pdf label", err)
+	}
+	if _, _, err := reply.CommercialInvoiceDataAndImageType(); err != nil {
+		t.Fatal("international shipment should have a commercial invoice", err)
+	}
+}
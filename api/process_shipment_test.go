@@ -1,8 +1,14 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/happyreturns/fedex/fedextest"
 	"github.com/happyreturns/fedex/models"
 )
 
@@ -32,36 +38,7 @@ var (
 )
 
 func TestGroundShipmentNotInternational(t *testing.T) {
-	shipment := &models.Shipment{
-		FromAndTo: models.FromAndTo{
-			FromAddress: models.Address{
-				StreetLines:         []string{"1511 15th Street"},
-				City:                "Santa Monica",
-				StateOrProvinceCode: "CA",
-				PostalCode:          "90404",
-				CountryCode:         "US",
-			},
-			FromContact: models.Contact{
-				PersonName:  "Joe Customer",
-				PhoneNumber: "2045551234",
-			},
-			ToContact: models.Contact{
-				PersonName:  "Returns Department",
-				CompanyName: "FedEx",
-				PhoneNumber: "9015551234",
-			},
-			ToAddress: models.Address{
-				StreetLines:         []string{"1106 Broadway"},
-				City:                "Santa Monica",
-				StateOrProvinceCode: "CA",
-				PostalCode:          "90404",
-				CountryCode:         "US",
-			},
-		},
-		NotificationEmail: "NotificationEmail",
-		References:        []string{"My ship ground reference - rothy's", "order number blah"},
-		Service:           "FEDEX_GROUND",
-	}
+	shipment := groundShipment()
 	envelope, err := testAPI.processShipmentRequest(shipment)
 	if err != nil {
 		t.Fatal(err)
@@ -140,59 +117,7 @@ func TestGroundShipmentNotInternational(t *testing.T) {
 }
 
 func TestGroundShipmentInternational(t *testing.T) {
-	commodities := []models.Commodity{
-		{
-			NumberOfPieces:       1,
-			Description:          "Computer Keyboard",
-			CountryOfManufacture: "US",
-			Weight:               models.Weight{Units: "LB", Value: 10.0},
-			Quantity:             1,
-			QuantityUnits:        "pcs",
-			UnitPrice:            &models.Money{Currency: "USD", Amount: 25.00},
-			CustomsValue:         &models.Money{Currency: "USD", Amount: 30.00},
-		},
-		{
-			NumberOfPieces:       1,
-			Description:          "Computer Monitor",
-			CountryOfManufacture: "US",
-			Weight:               models.Weight{Units: "LB", Value: 5.0},
-			Quantity:             1,
-			QuantityUnits:        "pcs",
-			UnitPrice:            &models.Money{Currency: "USD", Amount: 214.42},
-			CustomsValue:         &models.Money{Currency: "USD", Amount: 381.12},
-		},
-	}
-	shipment := &models.Shipment{
-		FromAndTo: models.FromAndTo{
-			FromAddress: models.Address{
-				StreetLines:         []string{"1234 Main Street", "Suite 200"},
-				City:                "Winnipeg",
-				StateOrProvinceCode: "MB",
-				PostalCode:          "R2M4B5",
-				CountryCode:         "CA",
-			},
-			FromContact: models.Contact{
-				PersonName:  "Joe Customer",
-				PhoneNumber: "2045551234",
-			},
-			ToContact: models.Contact{
-				PersonName:  "Returns Department",
-				CompanyName: "FedEx",
-				PhoneNumber: "9015551234",
-			},
-			ToAddress: models.Address{
-				StreetLines:         []string{"3610 Hacks Cross Road", "First Floor"},
-				City:                "Memphis",
-				StateOrProvinceCode: "TN",
-				PostalCode:          "38125",
-				CountryCode:         "US",
-			},
-		},
-		NotificationEmail: "NotificationEmail",
-		References:        []string{"My ship ground reference - rothy's", "order number blah"},
-		Service:           "FEDEX_GROUND",
-		Commodities:       commodities,
-	}
+	shipment := internationalShipment()
 	envelope, err := testAPI.processShipmentRequest(shipment)
 	if err != nil {
 		t.Fatal(err)
@@ -281,3 +206,205 @@ func TestGroundShipmentInternational(t *testing.T) {
 		t.Fatal("ShippingDocumentSpecification doesn't match")
 	}
 }
+
+func groundShipment() *models.Shipment {
+	return &models.Shipment{
+		FromAndTo: models.FromAndTo{
+			FromAddress: models.Address{
+				StreetLines:         []string{"1511 15th Street"},
+				City:                "Santa Monica",
+				StateOrProvinceCode: "CA",
+				PostalCode:          "90404",
+				CountryCode:         "US",
+			},
+			FromContact: models.Contact{
+				PersonName:  "Joe Customer",
+				PhoneNumber: "2045551234",
+			},
+			ToContact: models.Contact{
+				PersonName:  "Returns Department",
+				CompanyName: "FedEx",
+				PhoneNumber: "9015551234",
+			},
+			ToAddress: models.Address{
+				StreetLines:         []string{"1106 Broadway"},
+				City:                "Santa Monica",
+				StateOrProvinceCode: "CA",
+				PostalCode:          "90404",
+				CountryCode:         "US",
+			},
+		},
+		NotificationEmail: "NotificationEmail",
+		References:        []string{"My ship ground reference - rothy's", "order number blah"},
+		Service:           "FEDEX_GROUND",
+	}
+}
+
+func internationalShipment() *models.Shipment {
+	commodities := []models.Commodity{
+		{
+			NumberOfPieces:       1,
+			Description:          "Computer Keyboard",
+			CountryOfManufacture: "US",
+			Weight:               models.Weight{Units: "LB", Value: 10.0},
+			Quantity:             1,
+			QuantityUnits:        "pcs",
+			UnitPrice:            &models.Money{Currency: "USD", Amount: 25.00},
+			CustomsValue:         &models.Money{Currency: "USD", Amount: 30.00},
+		},
+		{
+			NumberOfPieces:       1,
+			Description:          "Computer Monitor",
+			CountryOfManufacture: "US",
+			Weight:               models.Weight{Units: "LB", Value: 5.0},
+			Quantity:             1,
+			QuantityUnits:        "pcs",
+			UnitPrice:            &models.Money{Currency: "USD", Amount: 214.42},
+			CustomsValue:         &models.Money{Currency: "USD", Amount: 381.12},
+		},
+	}
+	return &models.Shipment{
+		FromAndTo: models.FromAndTo{
+			FromAddress: models.Address{
+				StreetLines:         []string{"1234 Main Street", "Suite 200"},
+				City:                "Winnipeg",
+				StateOrProvinceCode: "MB",
+				PostalCode:          "R2M4B5",
+				CountryCode:         "CA",
+			},
+			FromContact: models.Contact{
+				PersonName:  "Joe Customer",
+				PhoneNumber: "2045551234",
+			},
+			ToContact: models.Contact{
+				PersonName:  "Returns Department",
+				CompanyName: "FedEx",
+				PhoneNumber: "9015551234",
+			},
+			ToAddress: models.Address{
+				StreetLines:         []string{"3610 Hacks Cross Road", "First Floor"},
+				City:                "Memphis",
+				StateOrProvinceCode: "TN",
+				PostalCode:          "38125",
+				CountryCode:         "US",
+			},
+		},
+		NotificationEmail: "NotificationEmail",
+		References:        []string{"My ship ground reference - rothy's", "order number blah"},
+		Service:           "FEDEX_GROUND",
+		Commodities:       commodities,
+	}
+}
+
+func TestMultiPieceShipment(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	packages := []models.PackageDetail{
+		{Weight: models.Weight{Units: models.WeightUnitsLB, Value: 3}, References: []string{"bag-1"}},
+		{Weight: models.Weight{Units: models.WeightUnitsLB, Value: 5}, References: []string{"bag-2"}},
+		{
+			Weight:        models.Weight{Units: models.WeightUnitsLB, Value: 8},
+			Dimensions:    models.Dimensions{Length: 12, Width: 10, Height: 8, Units: models.DimensionsUnitsIn},
+			DeclaredValue: &models.Money{Currency: "USD", Amount: 250},
+		},
+	}
+	reply, err := a.ProcessShipment(&models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground", Packages: packages})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trackingNumbers := reply.TrackingNumbers()
+	if len(trackingNumbers) != 3 || trackingNumbers[0] == trackingNumbers[1] || trackingNumbers[1] == trackingNumbers[2] {
+		t.Fatal("every package should have its own tracking number", trackingNumbers)
+	}
+	labels, imageType, err := reply.LabelsDataAndImageType()
+	if err != nil || len(labels) != 3 || imageType != "PNG" {
+		t.Fatal("every package should have a png label", len(labels), err)
+	}
+
+	master := reply.CompletedShipmentDetail.MasterTrackingId.TrackingNumber
+	for idx, childReply := range reply.ChildReplies {
+		if childReply.CompletedShipmentDetail.MasterTrackingId.TrackingNumber != master {
+			t.Fatal("child packages should have the master tracking number", master)
+		}
+		if sequenceNumber := childReply.CompletedShipmentDetail.CompletedPackageDetails.SequenceNumber; sequenceNumber != strconv.Itoa(idx+2) {
+			t.Fatal("child packages should be in sequence, got", sequenceNumber)
+		}
+	}
+
+	for _, trackingNumber := range trackingNumbers {
+		if _, err := a.TrackByNumber(models.CarrierCodeFDXG, trackingNumber); err != nil {
+			t.Fatal("every package should be tracked", err)
+		}
+	}
+
+	single, err := a.Rate(&models.Rate{FromAndTo: fromAndTo, Packages: packages[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	several, err := a.Rate(&models.Rate{FromAndTo: fromAndTo, Packages: packages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	singleCost, _ := single.TotalCost()
+	severalCost, _ := several.TotalCost()
+	if severalCost.Amount <= singleCost.Amount {
+		t.Fatal("rating several packages should cost more than one", singleCost, severalCost)
+	}
+}
+
+func TestMultiPieceShipmentRollback(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	server.FailAfter(fedextest.EndpointShip, 1, fedextest.FailureInvalidAddress)
+	shipment := &models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground", Packages: []models.PackageDetail{{}, {}, {}}}
+	if reply, err := a.ProcessShipment(shipment); err == nil || reply != nil {
+		t.Fatal("failed package should fail the shipment, without a reply once rolled back", reply, err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 3 || !strings.Contains(requests[2].Body, "DeleteShipmentRequest") ||
+		!strings.Contains(requests[2].Body, "DELETE_ALL_PACKAGES") {
+		t.Fatal("should delete the shipped packages after the failed one", len(requests))
+	}
+
+	server.FailAfter(fedextest.EndpointShip, 1, fedextest.FailureInvalidAddress, fedextest.FailureServiceUnavailable, fedextest.FailureServiceUnavailable)
+	reply, err := a.ProcessShipment(shipment)
+	if err == nil || reply == nil || len(reply.TrackingNumbers()) != 1 {
+		t.Fatal("should return the master package when it can't be deleted", reply, err)
+	}
+}
+
+type cancelAfter struct {
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if c.n--; c.n == 0 {
+		c.cancel()
+	}
+	return resp, err
+}
+
+func TestMultiPieceShipmentRollbackCancelled(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a.Transport = &cancelAfter{n: 1, cancel: cancel}
+
+	shipment := &models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground", Packages: []models.PackageDetail{{}, {}, {}}}
+	if reply, err := a.ProcessShipmentContext(ctx, shipment); !errors.Is(err, context.Canceled) || reply != nil {
+		t.Fatal("cancelled shipment should fail, without a reply once rolled back", reply, err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || !strings.Contains(requests[1].Body, "DeleteShipmentRequest") {
+		t.Fatal("should delete the master package even though the context is cancelled", len(requests))
+	}
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,27 +13,57 @@ const (
 )
 
 func (a API) Rate(rate *models.Rate) (*models.RateReply, error) {
+	return a.RateContext(context.Background(), rate)
+}
 
+// RateContext is like Rate but aborts the request when ctx is done
+func (a API) RateContext(ctx context.Context, rate *models.Rate) (*models.RateReply, error) {
 	endpoint := fmt.Sprintf("/rate/%s", rateVersion)
-	request := a.rateRequest(rate)
+	request := a.rateRequest(rate, time.Now(), false)
 	response := &models.RateResponseEnvelope{}
 
-	err := a.makeRequestAndUnmarshalResponse(endpoint, request, response)
+	err := a.makeRequestAndUnmarshalResponse(ctx, "Rate", endpoint, request, response)
 	if err != nil {
-		return nil, fmt.Errorf("make rate request and unmarshal: %s", err)
+		return nil, fmt.Errorf("make rate request and unmarshal: %w", err)
 	}
 
 	return &response.Reply, nil
 }
 
-func (a API) rateRequest(rate *models.Rate) *models.Envelope {
+// RateShop rates every service FedEx has for rate, instead of only
+// rate.Service, with their transit times
+func (a API) RateShop(rate *models.Rate) (models.RateQuotes, error) {
+	return a.RateShopContext(context.Background(), rate)
+}
+
+// RateShopContext is like RateShop but aborts the request when ctx is done
+func (a API) RateShopContext(ctx context.Context, rate *models.Rate) (models.RateQuotes, error) {
+	endpoint := fmt.Sprintf("/rate/%s", rateVersion)
+	shipTime := time.Now()
+	request := a.rateRequest(rate, shipTime, true)
+	response := &models.RateResponseEnvelope{}
+
+	err := a.makeRequestAndUnmarshalResponse(ctx, "Rate", endpoint, request, response)
+	if err != nil {
+		return nil, fmt.Errorf("make rate shop request and unmarshal: %w", err)
+	}
+
+	return response.Reply.Quotes(shipTime), nil
+}
+
+// rateRequest returns the request rating rate. When shopping, it rates every
+// service with their transit times.
+func (a API) rateRequest(rate *models.Rate, shipTime time.Time, shop bool) *models.Envelope {
 	rateRequestTypes := models.RequestTypePreferred
-	packageCount := 1
+	requestedPackageLineItems := rate.RequestedPackageLineItems()
+	packageCount := len(requestedPackageLineItems)
 
 	// When the service type is smartpost, getting rates from FedEx API doesn't
 	// work
 	serviceType := rate.ServiceType()
-	weight := rate.Weight()
+	if shop {
+		serviceType = ""
+	}
 
 	return &models.Envelope{
 		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
@@ -58,8 +89,9 @@ func (a API) rateRequest(rate *models.Rate) *models.Envelope {
 						Major:     24,
 					},
 				},
+				ReturnTransitAndCommit: shop,
 				RequestedShipment: models.RequestedShipment{
-					ShipTimestamp:     models.Timestamp(time.Now()),
+					ShipTimestamp:     models.Timestamp(shipTime),
 					DropoffType:       models.DropoffTypeRegularPickup,
 					ServiceType:       serviceType,
 					PackagingType:     models.PackagingTypeYourPackaging,
@@ -88,29 +120,9 @@ func (a API) rateRequest(rate *models.Rate) *models.Envelope {
 						LabelFormatType: models.LabelFormatTypeCommon2D,
 						ImageType:       models.ImageTypePDF,
 					},
-					RateRequestTypes: &rateRequestTypes,
-					PackageCount:     &packageCount,
-					RequestedPackageLineItems: []models.RequestedPackageLineItem{
-						{
-							SequenceNumber:    1,
-							GroupPackageCount: 1,
-							Weight:            weight,
-							Dimensions: models.Dimensions{
-								Length: 5,
-								Width:  5,
-								Height: 5,
-								Units:  models.DimensionsUnitsIn,
-							},
-							PhysicalPackaging: models.PackagingBag,
-							ItemDescription:   "Stuff",
-							CustomerReferences: []models.CustomerReference{
-								{
-									CustomerReferenceType: models.CustomerReferenceTypeCustomerReference,
-									Value:                 models.CustomerReferenceValueNaftaCoo,
-								},
-							},
-						},
-					},
+					RateRequestTypes:          &rateRequestTypes,
+					PackageCount:              &packageCount,
+					RequestedPackageLineItems: requestedPackageLineItems,
 				},
 			},
 		},
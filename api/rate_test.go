@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestRate(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	light, err := a.Rate(&models.Rate{FromAndTo: fromAndTo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	heavy, err := a.Rate(&models.Rate{FromAndTo: fromAndTo, Commodities: models.Commodities{
+		{Weight: models.Weight{Units: models.WeightUnitsLB, Value: 100}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lightCost, err := light.TotalCost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	heavyCost, err := heavy.TotalCost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if heavyCost.Amount <= lightCost.Amount {
+		t.Fatal("heavier packages should be more expensive", lightCost, heavyCost)
+	}
+}
+
+func TestRateShop(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	quotes, err := a.RateShop(&models.Rate{FromAndTo: fromAndTo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quotes) < 2 {
+		t.Fatal("should quote several services, got", len(quotes))
+	}
+
+	cheapest, err := quotes.Cheapest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fastest, err := quotes.Fastest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cheapest.ServiceType != "FEDEX_GROUND" || cheapest.MoneyBackGuarantee {
+		t.Fatal("ground should be the cheapest, without money back guarantee", cheapest)
+	}
+	if fastest.ServiceType == cheapest.ServiceType || !fastest.MoneyBackGuarantee {
+		t.Fatal("express should be the fastest", fastest)
+	}
+
+	if quote, err := quotes.CheapestArrivingBy(*fastest.Delivery); err != nil || quote == nil || quote.ServiceType != fastest.ServiceType {
+		t.Fatal("only the fastest should arrive by its delivery", quote, err)
+	}
+	if quote, err := quotes.CheapestArrivingBy(cheapest.Delivery.Add(time.Hour)); err != nil || quote == nil || quote.ServiceType != cheapest.ServiceType {
+		t.Fatal("the cheapest should arrive by its delivery", quote, err)
+	}
+
+	quotes[0].TotalNetCharge.Currency = "CAD"
+	if _, err := quotes.Cheapest(); !errors.Is(err, models.ErrMixedCurrencies) {
+		t.Fatal("quotes in different currencies should not be compared", err)
+	}
+}
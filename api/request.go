@@ -1,53 +1,74 @@
 package api
 
 import (
+	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/happyreturns/fedex/models"
-	"github.com/sirupsen/logrus"
 )
 
-var (
-	logger *logrus.Entry
-)
-
-func init() {
-	logger = logrus.WithFields(logrus.Fields{
-		"app": "fedex",
-	})
-}
-
-func (a API) makeRequestAndUnmarshalResponse(url string, request *models.Envelope, response models.Response) error {
+func (a API) makeRequestAndUnmarshalResponse(ctx context.Context, operation, url string, request *models.Envelope, response models.Response) error {
 	// Create request body
 	reqXML, err := xml.Marshal(request)
 	if err != nil {
-		return fmt.Errorf("marshal request xml: %s", err)
+		return fmt.Errorf("marshal request xml: %w", err)
+	}
+
+	policy := a.retryPolicy()
+	for attempt := 1; ; attempt++ {
+		err = a.attemptRequest(ctx, operation, url, string(reqXML), response)
+		if err == nil || attempt >= policy.MaxAttempts || !shouldRetry(ctx, operation, err) {
+			return err
+		}
+
+		backoff := policy.backoff(attempt - 1)
+		a.EffectiveLogger().Info("retrying-request", Fields{
+			"url":     url,
+			"attempt": attempt,
+			"backoff": backoff,
+			"err":     redactError(err, string(reqXML)),
+		})
+		if sleepErr := sleep(ctx, backoff); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// attemptRequest posts reqXML once and unmarshals the reply into response
+func (a API) attemptRequest(ctx context.Context, operation, url, reqXML string, response models.Response) error {
+	if a.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.Timeout)
+		defer cancel()
 	}
 
 	// Post XML
-	content, err := postXML(a.FedExURL+url, string(reqXML))
+	content, err := a.postXML(ctx, a.FedExURL+url, reqXML)
 	if err != nil {
-		logger.WithFields(logrus.Fields{
+		a.EffectiveLogger().Error("error-posting-xml", Fields{
 			"url":     url,
-			"request": string(reqXML),
-			"err":     err,
-		}).Error("error-posting-xml")
-		return fmt.Errorf("post xml: %s", err)
+			"request": RedactXML(reqXML),
+			"err":     redactError(err, reqXML),
+		})
+		return fmt.Errorf("post xml: %w", err)
 	}
 
-	// Parse response
+	// Parse response, clearing anything left from a previous attempt
+	reflect.ValueOf(response).Elem().Set(reflect.Zero(reflect.TypeOf(response).Elem()))
 	if err := xml.Unmarshal(content, response); err != nil {
-		logger.WithFields(logrus.Fields{
+		a.EffectiveLogger().Error("error-parsing-xml", Fields{
 			"url":      url,
-			"request":  string(reqXML),
-			"response": string(content),
-			"err":      err,
-		}).Error("error-parsing-xml")
-		return fmt.Errorf("parse xml: %s", err)
+			"request":  RedactXML(reqXML),
+			"response": RedactXML(string(content)),
+			"err":      redactError(err, reqXML),
+		})
+		return fmt.Errorf("parse xml: %w", err)
 	}
 
 	// Check if reply failed (FedEx responds with 200 even though it failed)
@@ -59,25 +80,50 @@ func (a API) makeRequestAndUnmarshalResponse(url string, request *models.Envelop
 		//   --> we DO NOT log an error, it is not an error from the logging perspective
 		//   --> this is still considered an error from the code-level perspective,
 		//       so we still return the error
-		if false == strings.Contains(err.Error(), "This tracking number cannot be found") {
-			logger.WithFields(logrus.Fields{
+		if !errors.Is(err, models.ErrTrackingNotFound) {
+			a.EffectiveLogger().Error("error-response", Fields{
 				"url":      url,
-				"request":  string(reqXML),
-				"response": string(content),
-				"err":      err,
-			}).Error("error-response")
+				"request":  RedactXML(reqXML),
+				"response": RedactXML(string(content)),
+				"err":      redactError(err, reqXML),
+			})
 		}
 
 		// return the error, even if we didn't log it
-		return fmt.Errorf("response error: %s", err)
+		return fmt.Errorf("response error: %w", err)
+	}
+
+	// In strict mode, fail on warnings the caller can't accept
+	if response, ok := response.(warningsResponse); ok && len(a.StrictWarningCodes) > 0 {
+		if err := models.WarningError(operation, response.Warnings(), a.StrictWarningCodes); err != nil {
+			a.EffectiveLogger().Error("strict-warning-response", Fields{
+				"url":      url,
+				"request":  RedactXML(reqXML),
+				"response": RedactXML(string(content)),
+				"err":      redactError(err, reqXML),
+			})
+			return fmt.Errorf("response warning: %w", err)
+		}
 	}
 
 	return nil
 }
 
-// postXML to Fedex API and return response
-func postXML(url, xml string) ([]byte, error) {
-	resp, err := http.Post(url, "text/xml", strings.NewReader(xml))
+// warningsResponse is a response that can succeed with warnings
+type warningsResponse interface {
+	Warnings() []models.Warning
+}
+
+// postXML to Fedex API and return response. The request is aborted as soon as
+// ctx is cancelled or its deadline passes.
+func (a API) postXML(ctx context.Context, url, xml string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(xml))
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/xml")
+
+	resp, err := a.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +131,13 @@ func postXML(url, xml string) ([]byte, error) {
 
 	content, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read all bytes: %s", err)
+		return nil, fmt.Errorf("read all bytes: %w", err)
+	}
+	if err := transportError(resp.StatusCode, content); err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, errEmptyResponse
 	}
 	return content, nil
 }
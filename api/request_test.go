@@ -0,0 +1,234 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestTrackByNumberContextDeadline(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	a := testAPI
+	a.FedExURL = server.URL
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := a.TrackByNumberContext(ctx, "FDXG", "123456789012")
+	if err == nil {
+		t.Fatal("should have failed once the deadline passed")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatal("request should have been aborted at the deadline, took", elapsed)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestTransport(t *testing.T) {
+	var requestedURL string
+	a := testAPI
+	a.FedExURL = "https://fedex.invalid"
+	a.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requestedURL = r.URL.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`<Envelope><Body><TrackReply><HighestSeverity>SUCCESS</HighestSeverity></TrackReply></Body></Envelope>`)),
+		}, nil
+	})
+
+	reply, err := a.TrackByNumber("FDXG", "123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.HighestSeverity != "SUCCESS" {
+		t.Fatal("reply should come from the transport")
+	}
+	if requestedURL != "https://fedex.invalid/trck" {
+		t.Fatal("unexpected url", requestedURL)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	a := testAPI
+	a.FedExURL = server.URL
+	a.Timeout = 50 * time.Millisecond
+	a.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+
+	if _, err := a.Rate(&models.Rate{}); err == nil {
+		t.Fatal("should have timed out")
+	}
+}
+
+func TestReplyError(t *testing.T) {
+	a := testAPI
+	a.FedExURL = "https://fedex.invalid"
+	a.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`<Envelope><Body><TrackReply>
+				<HighestSeverity>SUCCESS</HighestSeverity>
+				<TransactionDetail><CustomerTransactionId>Track Request</CustomerTransactionId></TransactionDetail>
+				<CompletedTrackDetails><TrackDetails>
+					<Notification><Severity>ERROR</Severity><Source>trck</Source><Code>9040</Code><Message>This tracking number cannot be found. Please check the number or contact the sender.</Message></Notification>
+				</TrackDetails></CompletedTrackDetails>
+			</TrackReply></Body></Envelope>`)),
+		}, nil
+	})
+
+	_, err := a.TrackByNumber("FDXG", "123456789012")
+	if !errors.Is(err, models.ErrTrackingNotFound) {
+		t.Fatal("should be tracking not found", err)
+	}
+	if errors.Is(err, models.ErrPickupAlreadyExists) {
+		t.Fatal("should not be pickup already exists")
+	}
+
+	var replyErr *models.ReplyError
+	if !errors.As(err, &replyErr) {
+		t.Fatal("should wrap a reply error")
+	}
+	if replyErr.Operation != "Track" ||
+		replyErr.TransactionID != "Track Request" ||
+		replyErr.HighestSeverity != "ERROR" ||
+		len(replyErr.Notifications) != 1 ||
+		replyErr.Notifications[0].Code != "9040" {
+		t.Fatal("reply error doesn't match", replyErr)
+	}
+}
+
+func TestStrictWarningCodes(t *testing.T) {
+	a := testAPI
+	a.FedExURL = "https://fedex.invalid"
+	a.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`<Envelope><Body><RateReply>
+				<HighestSeverity>WARNING</HighestSeverity>
+				<Notifications><Severity>WARNING</Severity><Source>crs</Source><Code>556</Code><Message>There are no valid services available.</Message></Notifications>
+				<Notifications><Severity>NOTE</Severity><Source>crs</Source><Code>819</Code><Message>The origin state/province code has been changed.</Message></Notifications>
+			</RateReply></Body></Envelope>`)),
+		}, nil
+	})
+
+	reply, err := a.Rate(&models.Rate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := reply.Warnings(); len(warnings) != 2 ||
+		warnings[0].Code != "556" ||
+		warnings[0].IsNote() ||
+		warnings[1].Code != "819" ||
+		!warnings[1].IsNote() {
+		t.Fatal("warnings don't match", warnings)
+	}
+
+	a.StrictWarningCodes = []string{"819"}
+	_, err = a.Rate(&models.Rate{})
+	var replyErr *models.ReplyError
+	if !errors.As(err, &replyErr) {
+		t.Fatal("should fail with a reply error", err)
+	}
+	if replyErr.Operation != "Rate" ||
+		len(replyErr.Notifications) != 1 ||
+		replyErr.Notifications[0].Code != "819" {
+		t.Fatal("reply error doesn't match", replyErr)
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   TransportError
+	}{
+		{
+			name:       "soap-fault",
+			statusCode: http.StatusInternalServerError,
+			body: `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body><soapenv:Fault>
+				<faultcode>soapenv:Server</faultcode>
+				<faultstring>Fault</faultstring>
+				<detail><desc>Internal error</desc></detail>
+			</soapenv:Fault></soapenv:Body></soapenv:Envelope>`,
+			expected: TransportError{
+				StatusCode:  http.StatusInternalServerError,
+				FaultCode:   "soapenv:Server",
+				FaultString: "Fault",
+				FaultDetail: "<desc>Internal error</desc>",
+			},
+		},
+		{
+			name:       "soap-fault-with-200",
+			statusCode: http.StatusOK,
+			body:       `<Envelope><Body><Fault><faultcode>soapenv:Client</faultcode><faultstring>Unmarshalling Error</faultstring></Fault></Body></Envelope>`,
+			expected: TransportError{
+				StatusCode:  http.StatusOK,
+				FaultCode:   "soapenv:Client",
+				FaultString: "Unmarshalling Error",
+			},
+		},
+		{
+			name:       "html-page",
+			statusCode: http.StatusBadGateway,
+			body:       "<html><body>" + strings.Repeat("Bad Gateway ", 100) + "</body></html>",
+			expected: TransportError{
+				StatusCode: http.StatusBadGateway,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			a := testAPI
+			a.FedExURL = "https://fedex.invalid"
+			a.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+			a.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: testCase.statusCode,
+					Body:       ioutil.NopCloser(strings.NewReader(testCase.body)),
+				}, nil
+			})
+
+			_, err := a.Rate(&models.Rate{})
+			var transportErr *TransportError
+			if !errors.As(err, &transportErr) {
+				t.Fatal("should be a transport error", err)
+			}
+			if transportErr.StatusCode != testCase.expected.StatusCode ||
+				transportErr.FaultCode != testCase.expected.FaultCode ||
+				transportErr.FaultString != testCase.expected.FaultString ||
+				transportErr.FaultDetail != testCase.expected.FaultDetail {
+				t.Fatal("transport error doesn't match", transportErr)
+			}
+			if len(transportErr.Body) > maxErrorBodyLength+len("...") {
+				t.Fatal("body should be truncated")
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// RetryPolicy controls how calls to FedEx are retried after transient
+// failures
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. One or
+	// less disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. It doubles on each
+	// following retry, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Jitter randomizes each wait by up to this fraction of it, so that clients
+	// failing together don't retry in lockstep
+	Jitter float64
+}
+
+// DefaultRetryPolicy is used when API.RetryPolicy is nil
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     4 * time.Second,
+	Jitter:         0.2,
+}
+
+// idempotentOperations are retried after any transient failure. The others,
+// like ProcessShipment and CreatePickup, may have been completed by FedEx even
+// though we never got the reply, and retrying them could create a duplicate
+// label or pickup. So they're only retried when the failure shows FedEx never
+// acted on the request.
+var idempotentOperations = map[string]bool{
+	"AddressValidation":     true,
+	"GetPickupAvailability": true,
+	"GetTrackingDocuments":  true,
+	"Rate":                  true,
+	"SearchLocations":       true,
+	"ServiceAvailability":   true,
+	"Track":                 true,
+	"UploadImages":          true,
+	"ValidatePostal":        true,
+}
+
+var errEmptyResponse = errors.New("empty response")
+
+type failure int
+
+const (
+	// failurePermanent won't go away by retrying
+	failurePermanent failure = iota
+	// failureTransient may go away, but FedEx may have acted on the request
+	failureTransient
+	// failureUnprocessed may go away, and FedEx never acted on the request
+	failureUnprocessed
+)
+
+func classifyFailure(ctx context.Context, err error) failure {
+	if ctx.Err() != nil {
+		return failurePermanent
+	}
+
+	var (
+		opErr        *net.OpError
+		netErr       net.Error
+		transportErr *TransportError
+	)
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return failureUnprocessed
+	case errors.As(err, &transportErr):
+		switch {
+		case transportErr.StatusCode == http.StatusServiceUnavailable,
+			transportErr.StatusCode == http.StatusTooManyRequests:
+			return failureUnprocessed
+		// a gateway may fail after FedEx processed the request, so 502 is
+		// only transient
+		case transportErr.StatusCode >= http.StatusInternalServerError:
+			return failureTransient
+		default:
+			return failurePermanent
+		}
+	case errors.Is(err, models.ErrServiceUnavailable):
+		return failureUnprocessed
+	case errors.As(err, &netErr), errors.Is(err, errEmptyResponse):
+		return failureTransient
+	default:
+		return failurePermanent
+	}
+}
+
+// shouldRetry reports whether operation can be retried after err
+func shouldRetry(ctx context.Context, operation string, err error) bool {
+	switch classifyFailure(ctx, err) {
+	case failureUnprocessed:
+		return true
+	case failureTransient:
+		return idempotentOperations[operation]
+	default:
+		return false
+	}
+}
+
+func (a API) retryPolicy() RetryPolicy {
+	if a.RetryPolicy == nil {
+		return DefaultRetryPolicy
+	}
+	return *a.RetryPolicy
+}
+
+// backoff returns how long to wait before the given retry, starting at 0
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 0; i < retry && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		backoff += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(backoff))
+	}
+	return backoff
+}
+
+// sleep waits for d, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
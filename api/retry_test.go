@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+var fastRetryPolicy = &RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Jitter:         0.2,
+}
+
+// failingServer fails the first numFailures requests with statusCode, then
+// replies with reply
+func failingServer(numFailures int32, statusCode int, reply string, attempts *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) <= numFailures {
+			w.WriteHeader(statusCode)
+			return
+		}
+		w.Write([]byte(reply))
+	}))
+}
+
+func TestRetryIdempotent(t *testing.T) {
+	var attempts int32
+	server := failingServer(2, http.StatusInternalServerError, `<Envelope><Body><RateReply><HighestSeverity>SUCCESS</HighestSeverity></RateReply></Body></Envelope>`, &attempts)
+	defer server.Close()
+
+	a := testAPI
+	a.FedExURL = server.URL
+	a.RetryPolicy = fastRetryPolicy
+
+	if _, err := a.Rate(&models.Rate{}); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Fatal("should have made 3 attempts, made", attempts)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var attempts int32
+	server := failingServer(5, http.StatusInternalServerError, "", &attempts)
+	defer server.Close()
+
+	a := testAPI
+	a.FedExURL = server.URL
+	a.RetryPolicy = fastRetryPolicy
+
+	if _, err := a.TrackByNumber("FDXG", "123456789012"); err == nil {
+		t.Fatal("should have failed")
+	}
+	if attempts != 3 {
+		t.Fatal("should have stopped after 3 attempts, made", attempts)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	shipReply := `<Envelope><Body><ProcessShipmentReply><HighestSeverity>SUCCESS</HighestSeverity></ProcessShipmentReply></Body></Envelope>`
+
+	for _, statusCode := range []int{http.StatusInternalServerError, http.StatusBadGateway} {
+		t.Run(fmt.Sprintf("maybe-processed-%d", statusCode), func(t *testing.T) {
+			var attempts int32
+			server := failingServer(1, statusCode, shipReply, &attempts)
+			defer server.Close()
+
+			a := testAPI
+			a.FedExURL = server.URL
+			a.RetryPolicy = fastRetryPolicy
+
+			if _, err := a.ProcessShipment(&models.Shipment{}); err == nil {
+				t.Fatal("should not retry a shipment FedEx may have created")
+			}
+			if attempts != 1 {
+				t.Fatal("should have made 1 attempt, made", attempts)
+			}
+		})
+	}
+
+	t.Run("unprocessed", func(t *testing.T) {
+		var attempts int32
+		server := failingServer(1, http.StatusServiceUnavailable, shipReply, &attempts)
+		defer server.Close()
+
+		a := testAPI
+		a.FedExURL = server.URL
+		a.RetryPolicy = fastRetryPolicy
+
+		if _, err := a.ProcessShipment(&models.Shipment{}); err != nil {
+			t.Fatal(err)
+		}
+		if attempts != 2 {
+			t.Fatal("should have made 2 attempts, made", attempts)
+		}
+	})
+}
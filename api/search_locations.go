@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	searchLocationsVersion = "v12"
+)
+
+// SearchLocations returns the FedEx locations, like FedEx Offices and drop
+// off points, within the radius of the searched address or coordinates,
+// nearest first
+func (a API) SearchLocations(search models.LocationSearch) ([]models.Location, error) {
+	return a.SearchLocationsContext(context.Background(), search)
+}
+
+// SearchLocationsContext is like SearchLocations but aborts the request when
+// ctx is done
+func (a API) SearchLocationsContext(ctx context.Context, search models.LocationSearch) ([]models.Location, error) {
+	if search.Address == nil && search.Coordinates == nil {
+		return nil, errors.New("search locations without address or coordinates")
+	}
+
+	endpoint := fmt.Sprintf("/locs/%s", searchLocationsVersion)
+	request := a.searchLocationsRequest(search)
+	response := &models.SearchLocationsResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "SearchLocations", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make search locations request and unmarshal: %w", err)
+	}
+
+	return response.Reply.Locations(), nil
+}
+
+func (a API) searchLocationsRequest(search models.LocationSearch) *models.Envelope {
+	criterion := models.LocationsSearchCriterionAddress
+	coordinates := ""
+	if search.Coordinates != nil {
+		criterion = models.LocationsSearchCriterionGeographicCoordinates
+		coordinates = search.Coordinates.String()
+	}
+
+	radiusUnits := search.RadiusUnits
+	if radiusUnits == "" {
+		radiusUnits = models.DistanceUnitsMI
+	}
+
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/locs/%s", searchLocationsVersion),
+		Body: models.SearchLocationsBody{
+			SearchLocationsRequest: models.SearchLocationsRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "locs",
+						Major:     12,
+					},
+				},
+				EffectiveDate:            time.Now().Format("2006-01-02"),
+				LocationsSearchCriterion: criterion,
+				Address:                  search.Address,
+				GeographicCoordinates:    coordinates,
+				MultipleMatchesAction:    "RETURN_ALL",
+				SortDetail: models.LocationSortDetail{
+					Criterion: "DISTANCE",
+					Order:     "LOWEST_TO_HIGHEST",
+				},
+				Constraints: models.SearchLocationConstraints{
+					RadiusDistance: models.Distance{
+						Value: search.Radius,
+						Units: radiusUnits,
+					},
+					ResultsRequested:       search.MaxResults,
+					LocationTypesToInclude: search.LocationTypes,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestSearchLocations(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	locations, err := a.SearchLocations(models.LocationSearch{Address: &fromAndTo.ToAddress, Radius: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 2 || locations[0].Distance.Value > locations[1].Distance.Value {
+		t.Fatal("should have the locations within 2 miles, nearest first", locations)
+	}
+	if locations[0].AcceptsSmartPostReturns || !locations[1].AcceptsSmartPostReturns {
+		t.Fatal("only the second location should accept SmartPost returns", locations)
+	}
+	if location := locations[1]; location.Contact.CompanyName != "Walgreens" || location.Address.City != "SANTA MONICA" ||
+		location.Coordinates == nil || location.Coordinates.Latitude != 34.0262 || len(location.Hours) != 7 {
+		t.Fatal("should have the normalized location", location)
+	}
+
+	locations, err = a.SearchLocations(models.LocationSearch{
+		Coordinates:   &models.GeographicCoordinates{Latitude: 34.0195, Longitude: -118.4912},
+		Radius:        10,
+		RadiusUnits:   models.DistanceUnitsKM,
+		LocationTypes: []string{models.LocationTypeFedexAuthorizedShipCenter},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 1 || locations[0].Type != models.LocationTypeFedexAuthorizedShipCenter ||
+		locations[0].Distance.Units != models.DistanceUnitsKM {
+		t.Fatal("should only have the ship center", locations)
+	}
+
+	if _, err := a.SearchLocations(models.LocationSearch{Radius: 10}); err == nil {
+		t.Fatal("search without address or coordinates should fail")
+	}
+}
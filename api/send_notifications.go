@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/happyreturns/fedex/models"
@@ -12,19 +13,39 @@ const (
 
 // SendNotifications gets notifications sent to an email
 func (a API) SendNotifications(trackingNo, email string) (*models.SendNotificationsReply, error) {
+	return a.SendNotificationsContext(context.Background(), trackingNo, email)
+}
 
+// SendNotificationsContext is like SendNotifications but aborts the request
+// when ctx is done
+func (a API) SendNotificationsContext(ctx context.Context, trackingNo, email string) (*models.SendNotificationsReply, error) {
 	endpoint := fmt.Sprintf("/track/%s", sendNotificationsVersion)
-	request := a.sendNotificationsRequest(trackingNo, email)
+	request := a.sendNotificationsRequest(trackingNo, email, "")
 	response := &models.SendNotificationsResponseEnvelope{}
 
-	err := a.makeRequestAndUnmarshalResponse(endpoint, request, response)
+	err := a.makeRequestAndUnmarshalResponse(ctx, "SendNotifications", endpoint, request, response)
 	if err != nil {
-		return nil, fmt.Errorf("make send notifications request: %s", err)
+		return nil, fmt.Errorf("make send notifications request: %w", err)
+	}
+
+	// Follow the pages of packages
+	reply := &response.Reply
+	for page := 2; page <= maxTrackPages && reply.MoreDataAvailable && reply.PagingToken != ""; page++ {
+		request := a.sendNotificationsRequest(trackingNo, email, reply.PagingToken)
+		response := &models.SendNotificationsResponseEnvelope{}
+
+		err := a.makeRequestAndUnmarshalResponse(ctx, "SendNotifications", endpoint, request, response)
+		if err != nil {
+			return nil, fmt.Errorf("make send notifications request for page %d: %w", page, err)
+		}
+		reply.Packages = append(reply.Packages, response.Reply.Packages...)
+		reply.MoreDataAvailable = response.Reply.MoreDataAvailable
+		reply.PagingToken = response.Reply.PagingToken
 	}
-	return &response.Reply, nil
+	return reply, nil
 }
 
-func (a API) sendNotificationsRequest(trackingNo, email string) *models.Envelope {
+func (a API) sendNotificationsRequest(trackingNo, email, pagingToken string) *models.Envelope {
 	return &models.Envelope{
 		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
 		Namespace: fmt.Sprintf("http://fedex.com/ws/track/%s", sendNotificationsVersion),
@@ -47,6 +68,7 @@ func (a API) sendNotificationsRequest(trackingNo, email string) *models.Envelope
 					},
 				},
 				TrackingNumber:     trackingNo,
+				PagingToken:        pagingToken,
 				SenderEmailAddress: email,
 				SenderContactName:  "Customer",
 				EventNotificationDetail: models.EventNotificationDetail{
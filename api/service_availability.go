@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	serviceAvailabilityVersion = "v8"
+)
+
+// ServiceAvailability returns the services FedEx has between the addresses of
+// fromAndTo for packages shipped on shipDate, with when it commits to deliver
+// them. packaging is a packaging type like YOUR_PACKAGING, or empty for any.
+func (a API) ServiceAvailability(fromAndTo models.FromAndTo, shipDate time.Time, packaging string) (models.ServiceCommitments, error) {
+	return a.ServiceAvailabilityContext(context.Background(), fromAndTo, shipDate, packaging)
+}
+
+// ServiceAvailabilityContext is like ServiceAvailability but aborts the
+// request when ctx is done
+func (a API) ServiceAvailabilityContext(ctx context.Context, fromAndTo models.FromAndTo, shipDate time.Time, packaging string) (models.ServiceCommitments, error) {
+	endpoint := fmt.Sprintf("/vacs/%s", serviceAvailabilityVersion)
+	request := a.serviceAvailabilityRequest(fromAndTo, shipDate, packaging)
+	response := &models.ServiceAvailabilityResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "ServiceAvailability", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make service availability request and unmarshal: %w", err)
+	}
+
+	return response.Reply.Commitments(shipDate), nil
+}
+
+func (a API) serviceAvailabilityRequest(fromAndTo models.FromAndTo, shipDate time.Time, packaging string) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/vacs/%s", serviceAvailabilityVersion),
+		Body: models.ServiceAvailabilityBody{
+			ServiceAvailabilityRequest: models.ServiceAvailabilityRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "vacs",
+						Major:     8,
+					},
+				},
+				Origin:      fromAndTo.FromAddress,
+				Destination: fromAndTo.ToAddress,
+				ShipDate:    shipDate.Format("2006-01-02"),
+				Packaging:   packaging,
+			},
+		},
+	}
+}
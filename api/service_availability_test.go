@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestServiceAvailability(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	friday := time.Date(2020, time.October, 16, 0, 0, 0, 0, time.UTC)
+	commitments, err := a.ServiceAvailability(fromAndTo, friday, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commitments) < 2 {
+		t.Fatal("should have several services, got", len(commitments))
+	}
+
+	ground := commitments.Find(models.ServiceTypeFedexGround)
+	if ground == nil || ground.TransitDays != 4 || ground.Commit == nil ||
+		!ground.Commit.Equal(time.Date(2020, time.October, 22, 0, 0, 0, 0, time.UTC)) || ground.DayOfWeek != "THU" {
+		t.Fatal("ground should be delivered in 4 business days", ground)
+	}
+	overnight := commitments.Find("STANDARD_OVERNIGHT")
+	if overnight == nil || overnight.Commit == nil ||
+		!overnight.Commit.Equal(time.Date(2020, time.October, 19, 0, 0, 0, 0, time.UTC)) || overnight.DayOfWeek != "MON" {
+		t.Fatal("overnight should be committed to the next business day", overnight)
+	}
+
+	commitments, err = a.ServiceAvailability(fromAndTo, friday, "FEDEX_ENVELOPE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commitments.Find(models.ServiceTypeFedexGround) != nil {
+		t.Fatal("ground should not be available for envelopes", commitments)
+	}
+
+	international := fromAndTo
+	international.ToAddress = models.Address{
+		StreetLines:         []string{"290 Bremner Blvd"},
+		City:                "Toronto",
+		StateOrProvinceCode: "ON",
+		PostalCode:          "M5V 3L9",
+		CountryCode:         "CA",
+	}
+	commitments, err = a.ServiceAvailability(international, friday, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commitments) != 1 || commitments.Find(models.ServiceTypeInternationalEconomy) == nil {
+		t.Fatal("only international economy should be available", commitments)
+	}
+}
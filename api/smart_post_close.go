@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// SmartPostClose closes the SmartPost shipments of the hub of the account.
+// FedEx doesn't return documents for SmartPost closes. It fails with
+// ErrNothingToClose when there are no shipments to close.
+func (a API) SmartPostClose() error {
+	return a.SmartPostCloseContext(context.Background())
+}
+
+// SmartPostCloseContext is like SmartPostClose but aborts the request when ctx
+// is done
+func (a API) SmartPostCloseContext(ctx context.Context) error {
+	if a.HubID == "" {
+		return errors.New("smartpost close without hub ID")
+	}
+
+	endpoint := fmt.Sprintf("/close/%s", closeVersion)
+	request := a.smartPostCloseRequest()
+	response := &models.SmartPostCloseResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "SmartPostClose", endpoint, request, response); err != nil {
+		return fmt.Errorf("make smartpost close request and unmarshal: %w", err)
+	}
+	return nil
+}
+
+func (a API) smartPostCloseRequest() *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/close/%s", closeVersion),
+		Body: models.SmartPostCloseBody{
+			SmartPostCloseRequest: models.SmartPostCloseRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "clos",
+						Major:     5,
+					},
+				},
+				HubID:         a.HubID,
+				PickUpCarrier: models.CarrierCodeFDXG,
+			},
+		},
+	}
+}
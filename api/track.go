@@ -1,23 +1,140 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/happyreturns/fedex/models"
 )
 
 func (a API) TrackByNumber(carrierCode, trackingNo string) (*models.TrackReply, error) {
-	request := a.trackByNumberRequest(carrierCode, trackingNo)
+	return a.TrackByNumberContext(context.Background(), carrierCode, trackingNo)
+}
+
+// TrackByNumberContext is like TrackByNumber but aborts the request when ctx
+// is done
+func (a API) TrackByNumberContext(ctx context.Context, carrierCode, trackingNo string) (*models.TrackReply, error) {
+	return a.trackSelection(ctx, trackingNumberSelection(carrierCode, trackingNo))
+}
+
+// TrackByUniqueIdentifier tracks the shipment with uniqueIdentifier, e.g. a
+// candidate of a duplicate waybill tracking number
+func (a API) TrackByUniqueIdentifier(carrierCode, trackingNo, uniqueIdentifier string) (*models.TrackReply, error) {
+	return a.TrackByUniqueIdentifierContext(context.Background(), carrierCode, trackingNo, uniqueIdentifier)
+}
+
+// TrackByUniqueIdentifierContext is like TrackByUniqueIdentifier but aborts
+// the request when ctx is done
+func (a API) TrackByUniqueIdentifierContext(ctx context.Context, carrierCode, trackingNo, uniqueIdentifier string) (*models.TrackReply, error) {
+	selection := trackingNumberSelection(carrierCode, trackingNo)
+	selection.TrackingNumberUniqueIdentifier = uniqueIdentifier
+	return a.trackSelection(ctx, selection)
+}
+
+func (a API) trackSelection(ctx context.Context, selection models.SelectionDetails) (*models.TrackReply, error) {
+	request := a.trackRequest(selection)
 	response := &models.TrackResponseEnvelope{}
 
-	err := a.makeRequestAndUnmarshalResponse("/trck", request, response)
+	err := a.makeRequestAndUnmarshalResponse(ctx, "Track", "/trck", request, response)
 	if err != nil {
-		return nil, fmt.Errorf("make track request and unmarshal: %s", err)
+		return nil, fmt.Errorf("make track request and unmarshal: %w", err)
+	}
+	if err := a.trackMorePages(ctx, selection, &response.Reply); err != nil {
+		return nil, err
+	}
+	return &response.Reply, nil
+}
+
+// TrackByReference tracks every package matching reference
+func (a API) TrackByReference(reference models.TrackReference) (*models.TrackReply, error) {
+	return a.TrackByReferenceContext(context.Background(), reference)
+}
+
+// TrackByReferenceContext is like TrackByReference but aborts the request when
+// ctx is done
+func (a API) TrackByReferenceContext(ctx context.Context, reference models.TrackReference) (*models.TrackReply, error) {
+	selection := a.referenceSelection(reference)
+	request := a.trackRequest(selection)
+	response := &models.TrackResponseEnvelope{}
+
+	err := a.makeRequestAndUnmarshalResponse(ctx, "Track", "/trck", request, response)
+	if err != nil {
+		return nil, fmt.Errorf("make track by reference request and unmarshal: %w", err)
+	}
+	if err := a.trackMorePages(ctx, selection, &response.Reply); err != nil {
+		return nil, err
 	}
 	return &response.Reply, nil
 }
 
-func (a API) trackByNumberRequest(carrierCode string, trackingNo string) *models.Envelope {
+// maxTrackPages bounds the pages followed for one selection. MoreData stays
+// set on replies with more.
+const maxTrackPages = 20
+
+// trackMorePages adds the track details of the next pages to the completed
+// track details of reply, tracked with selection, while FedEx has more
+func (a API) trackMorePages(ctx context.Context, selection models.SelectionDetails, reply *models.TrackReply) error {
+	for idx := range reply.CompletedTrackDetails {
+		completedTrackDetail := &reply.CompletedTrackDetails[idx]
+		for page := 2; page <= maxTrackPages && completedTrackDetail.MoreData && completedTrackDetail.PagingToken != ""; page++ {
+			selection.PagingDetail = &models.PagingDetail{PagingToken: completedTrackDetail.PagingToken}
+			response := &models.TrackManyResponseEnvelope{}
+
+			err := a.makeRequestAndUnmarshalResponse(ctx, "Track", "/trck", a.trackRequest(selection), response)
+			if err != nil {
+				return fmt.Errorf("make track request for page %d and unmarshal: %w", page, err)
+			}
+			if len(response.Reply.CompletedTrackDetails) == 0 {
+				break
+			}
+
+			next := response.Reply.CompletedTrackDetails[0]
+			if err := next.Error(); err != nil {
+				return fmt.Errorf("track page %d: %w", page, err)
+			}
+			completedTrackDetail.TrackDetails = append(completedTrackDetail.TrackDetails, next.TrackDetails...)
+			completedTrackDetail.MoreData = next.MoreData
+			completedTrackDetail.PagingToken = next.PagingToken
+		}
+	}
+	return nil
+}
+
+func (a API) referenceSelection(reference models.TrackReference) models.SelectionDetails {
+	selection := models.SelectionDetails{
+		CarrierCode:           reference.CarrierCode,
+		PackageIdentifier:     reference.PackageIdentifier(),
+		ShipmentAccountNumber: reference.ShipmentAccountNumber,
+	}
+	if !reference.ShipDateRangeBegin.IsZero() {
+		selection.ShipDateRangeBegin = reference.ShipDateRangeBegin.Format("2006-01-02")
+	}
+	if !reference.ShipDateRangeEnd.IsZero() {
+		selection.ShipDateRangeEnd = reference.ShipDateRangeEnd.Format("2006-01-02")
+	}
+
+	if reference.DestinationCountryCode != "" {
+		selection.Destination = &models.SelectionDestination{
+			PostalCode:  reference.DestinationPostalCode,
+			CountryCode: reference.DestinationCountryCode,
+		}
+	} else if selection.ShipmentAccountNumber == "" {
+		selection.ShipmentAccountNumber = a.Account
+	}
+	return selection
+}
+
+func trackingNumberSelection(carrierCode, trackingNo string) models.SelectionDetails {
+	return models.SelectionDetails{
+		CarrierCode: carrierCode,
+		PackageIdentifier: models.PackageIdentifier{
+			Type:  models.PackageIdentifierTypeTrackingNumber,
+			Value: trackingNo,
+		},
+	}
+}
+
+func (a API) trackRequest(selectionDetails ...models.SelectionDetails) *models.Envelope {
 	return &models.Envelope{
 		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
 		Namespace: "http://fedex.com/ws/track/v16",
@@ -40,13 +157,7 @@ func (a API) trackByNumberRequest(carrierCode string, trackingNo string) *models
 					},
 				},
 				ProcessingOptions: "INCLUDE_DETAILED_SCANS",
-				SelectionDetails: models.SelectionDetails{
-					CarrierCode: carrierCode,
-					PackageIdentifier: models.PackageIdentifier{
-						Type:  "TRACKING_NUMBER_OR_DOORTAG",
-						Value: trackingNo,
-					},
-				},
+				SelectionDetails:  selectionDetails,
 			},
 		},
 	}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	// MaxTrackSelectionDetails is the most tracking numbers FedEx tracks in
+	// one request
+	MaxTrackSelectionDetails = 30
+	// DefaultTrackConcurrency is how many track requests TrackMany sends at
+	// once when API.TrackConcurrency isn't set
+	DefaultTrackConcurrency = 4
+)
+
+// TrackResult is the result of tracking one of the numbers passed to
+// TrackMany. Either Reply or Err is set.
+type TrackResult struct {
+	TrackingNumber string
+	// Reply only has the CompletedTrackDetails of TrackingNumber
+	Reply *models.TrackReply
+	Err   error
+}
+
+// TrackMany tracks trackingNumbers, MaxTrackSelectionDetails per request.
+// Results are in the order of trackingNumbers, and a tracking number that
+// can't be tracked doesn't fail the others.
+func (a API) TrackMany(carrierCode string, trackingNumbers []string) []TrackResult {
+	return a.TrackManyContext(context.Background(), carrierCode, trackingNumbers)
+}
+
+// TrackManyContext is like TrackMany but aborts the requests when ctx is done
+func (a API) TrackManyContext(ctx context.Context, carrierCode string, trackingNumbers []string) []TrackResult {
+	results := make([]TrackResult, len(trackingNumbers))
+	for idx, trackingNumber := range trackingNumbers {
+		results[idx].TrackingNumber = trackingNumber
+	}
+
+	// Each chunk is tracked by one worker, which only writes the results of
+	// its chunk
+	chunks := make(chan []TrackResult)
+	numChunks := (len(results) + MaxTrackSelectionDetails - 1) / MaxTrackSelectionDetails
+	numWorkers := a.trackConcurrency()
+	if numWorkers > numChunks {
+		numWorkers = numChunks
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(numWorkers)
+	for worker := 0; worker < numWorkers; worker++ {
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				a.trackChunk(ctx, carrierCode, chunk)
+			}
+		}()
+	}
+
+	for start := 0; start < len(results); start += MaxTrackSelectionDetails {
+		end := start + MaxTrackSelectionDetails
+		if end > len(results) {
+			end = len(results)
+		}
+		chunks <- results[start:end]
+	}
+	close(chunks)
+	wg.Wait()
+
+	return results
+}
+
+func (a API) trackConcurrency() int {
+	if a.TrackConcurrency <= 0 {
+		return DefaultTrackConcurrency
+	}
+	return a.TrackConcurrency
+}
+
+// trackChunk tracks the tracking numbers of results in one request, and sets
+// the reply or error of each
+func (a API) trackChunk(ctx context.Context, carrierCode string, results []TrackResult) {
+	if err := ctx.Err(); err != nil {
+		setTrackError(results, fmt.Errorf("make track request and unmarshal: %w", err))
+		return
+	}
+
+	selectionDetails := make([]models.SelectionDetails, len(results))
+	for idx, result := range results {
+		selectionDetails[idx] = trackingNumberSelection(carrierCode, result.TrackingNumber)
+	}
+	request := a.trackRequest(selectionDetails...)
+	response := &models.TrackManyResponseEnvelope{}
+
+	err := a.makeRequestAndUnmarshalResponse(ctx, "Track", "/trck", request, response)
+	if err != nil {
+		setTrackError(results, fmt.Errorf("make track request and unmarshal: %w", err))
+		return
+	}
+
+	reply := response.Reply
+	for idx := range results {
+		completedTrackDetail := reply.CompletedTrackDetail(results[idx].TrackingNumber)
+		// FedEx replies in the order of the selection details, so fall back
+		// to that when the tracking number isn't echoed as sent
+		if completedTrackDetail == nil && len(reply.CompletedTrackDetails) == len(results) {
+			completedTrackDetail = &reply.CompletedTrackDetails[idx]
+		}
+		if completedTrackDetail == nil {
+			results[idx].Err = fmt.Errorf("track reply has no track detail for %s", results[idx].TrackingNumber)
+			continue
+		}
+		if err := completedTrackDetail.Error(); err != nil {
+			results[idx].Err = fmt.Errorf("track detail error: %w", err)
+			continue
+		}
+
+		result := &models.TrackReply{
+			Reply:                 reply.Reply,
+			CompletedTrackDetails: []models.CompletedTrackDetail{*completedTrackDetail},
+		}
+		if err := a.trackMorePages(ctx, selectionDetails[idx], result); err != nil {
+			results[idx].Err = err
+			continue
+		}
+		results[idx].Reply = result
+	}
+}
+
+func setTrackError(results []TrackResult, err error) {
+	for idx := range results {
+		results[idx].Err = err
+	}
+}
@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/api"
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestTrackMany(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+
+	a := api.TestAPI
+	a.FedExURL = server.URL
+	a.Logger = api.NopLogger{}
+	a.RetryPolicy = &api.RetryPolicy{MaxAttempts: 1}
+	a.TrackConcurrency = 1
+
+	// Every third tracking number is unknown
+	trackingNumbers := make([]string, 65)
+	for idx := range trackingNumbers {
+		trackingNumbers[idx] = fmt.Sprintf("7940000%05d", idx)
+		if idx%3 != 0 {
+			server.SetTracking(trackingNumbers[idx], fedextest.Tracking{
+				CarrierCode: "FDXG",
+				ShipTime:    time.Date(2020, 10, 1, 9, 30, 0, 0, time.UTC),
+			})
+		}
+	}
+
+	// The first request fails as a whole
+	server.FailNext(fedextest.EndpointTrack, fedextest.FailureAuthentication)
+
+	results := a.TrackMany("FDXG", trackingNumbers)
+	if len(results) != len(trackingNumbers) {
+		t.Fatal("should have a result per tracking number, got", len(results))
+	}
+	if numRequests := len(server.Requests()); numRequests != 3 {
+		t.Fatal("should have tracked 30 numbers per request, got requests:", numRequests)
+	}
+
+	for idx, result := range results {
+		switch {
+		case result.TrackingNumber != trackingNumbers[idx]:
+			t.Fatal("results should be in order", idx, result.TrackingNumber)
+		case idx < api.MaxTrackSelectionDetails:
+			if !errors.Is(result.Err, models.ErrAuthFailure) {
+				t.Fatal("first request should have failed authentication", idx, result.Err)
+			}
+		case idx%3 == 0:
+			if !errors.Is(result.Err, models.ErrTrackingNotFound) || result.Reply != nil {
+				t.Fatal("unknown tracking number should not be found", idx, result.Err)
+			}
+		default:
+			if result.Err != nil {
+				t.Fatal(idx, result.Err)
+			}
+			if len(result.Reply.CompletedTrackDetails) != 1 ||
+				result.Reply.CompletedTrackDetails[0].TrackDetails[0].TrackingNumber != result.TrackingNumber {
+				t.Fatal("reply should only have the tracking number", idx)
+			}
+			if result.Reply.Ship() == nil {
+				t.Fatal("reply should have the ship time", idx)
+			}
+		}
+	}
+}
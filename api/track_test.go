@@ -0,0 +1,173 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestTrackByReference(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	for _, rmaNumber := range []string{"RMA-1234", "RMA-1234", "RMA-5678"} {
+		if _, err := a.ProcessShipment(&models.Shipment{FromAndTo: fromAndTo, RMANumber: rmaNumber}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reply, err := a.TrackByReference(models.TrackReference{
+		Type:               models.PackageIdentifierTypeRMA,
+		Value:              "RMA-1234",
+		ShipDateRangeBegin: time.Now().AddDate(0, 0, -7),
+		ShipDateRangeEnd:   time.Now().AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trackDetails := reply.TrackDetails(); len(trackDetails) != 2 {
+		t.Fatal("should have tracked both packages with the RMA, got", len(trackDetails))
+	}
+
+	_, err = a.TrackByReference(models.TrackReference{
+		Type:                   models.PackageIdentifierTypeRMA,
+		Value:                  "RMA-1234",
+		DestinationPostalCode:  "10001",
+		DestinationCountryCode: "US",
+	})
+	if !errors.Is(err, models.ErrTrackingNotFound) {
+		t.Fatal("packages shipped elsewhere should not be found", err)
+	}
+}
+
+func TestDuplicateWaybill(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	older := time.Date(2019, 3, 1, 9, 0, 0, 0, time.UTC)
+	newer := time.Date(2020, 10, 1, 9, 0, 0, 0, time.UTC)
+	server.SetDuplicateTracking("794000000050",
+		fedextest.Tracking{CarrierCode: models.CarrierCodeFDXG, ShipTime: older, ActualDelivery: older.Add(48 * time.Hour)},
+		fedextest.Tracking{CarrierCode: models.CarrierCodeFDXG, ShipTime: newer},
+	)
+
+	reply, err := a.TrackByNumber(models.CarrierCodeFDXG, "794000000050")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reply.DuplicateWaybill() || len(reply.Candidates()) != 2 {
+		t.Fatal("should have both candidates")
+	}
+	if reply.ActualDelivery() != nil {
+		t.Fatal("should not report the delivery of the older shipment")
+	}
+
+	candidate := reply.CandidateShippedOn(older)
+	if candidate == nil {
+		t.Fatal("should find the older shipment by ship date")
+	}
+	reply, err = a.TrackByUniqueIdentifier(models.CarrierCodeFDXG, "794000000050", candidate.TrackingNumberUniqueIdentifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.DuplicateWaybill() || reply.ActualDelivery() == nil {
+		t.Fatal("should track the older shipment")
+	}
+}
+
+func TestTrackPaging(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+	server.SetTrackPageSize(2)
+
+	for idx := 0; idx < 5; idx++ {
+		if _, err := a.ProcessShipment(&models.Shipment{FromAndTo: fromAndTo, InvoiceNumber: "INV-1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reply, err := a.TrackByReference(models.TrackReference{Type: models.PackageIdentifierTypeInvoice, Value: "INV-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trackDetails := reply.TrackDetails(); len(trackDetails) != 5 || reply.CompletedTrackDetails[0].MoreData {
+		t.Fatal("should have followed the pages, got track details:", len(trackDetails))
+	}
+}
+
+func TestTrackingTimeline(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	shipTime := time.Date(2020, 10, 1, 9, 30, 0, 0, time.UTC)
+	deliveryTime := shipTime.Add(50 * time.Hour)
+	server.SetTracking("794000000042", fedextest.Tracking{
+		CarrierCode:           models.CarrierCodeFDXG,
+		ShipTime:              shipTime,
+		ActualDelivery:        deliveryTime,
+		DeliverySignatureName: "J.DOE",
+		Events: []fedextest.TrackingEvent{
+			{Timestamp: deliveryTime, EventType: "DL", EventDescription: "Delivered"},
+			{Timestamp: shipTime.Add(24 * time.Hour), EventType: "IT", EventDescription: "In transit"},
+			{Timestamp: shipTime, EventType: "PU", EventDescription: "Picked up"},
+		},
+	})
+
+	reply, err := a.TrackByNumber(models.CarrierCodeFDXG, "794000000042")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualDelivery := reply.ActualDelivery(); actualDelivery == nil || !actualDelivery.Equal(deliveryTime) {
+		t.Fatal("actual delivery doesn't match", actualDelivery)
+	}
+	if ship := reply.Ship(); ship == nil || !ship.Equal(shipTime) {
+		t.Fatal("ship doesn't match", ship)
+	}
+	if events := reply.Events(); len(events) != 3 || events[0].EventType != "DL" {
+		t.Fatal("events don't match", events)
+	}
+
+	trackDetail := reply.CompletedTrackDetails[0].TrackDetails[0]
+	if status := trackDetail.Status(); status != models.TrackingStatusDelivered {
+		t.Fatal("should be delivered, got", status)
+	}
+	timeline := trackDetail.Timeline()
+	if len(timeline) != 3 || timeline[0].Status() != models.TrackingStatusPickedUp || timeline[2].Status() != models.TrackingStatusDelivered {
+		t.Fatal("timeline should be oldest first", timeline)
+	}
+
+	server.SetTracking("794000000043", fedextest.Tracking{
+		CarrierCode: models.CarrierCodeFDXG,
+		Events: []fedextest.TrackingEvent{
+			{Timestamp: deliveryTime, EventType: "DL", EventDescription: "Delivered"},
+			{Timestamp: shipTime.Add(24 * time.Hour), EventType: "RS", EventDescription: "Return to shipper"},
+			{Timestamp: shipTime, EventType: "PU", EventDescription: "Picked up"},
+		},
+	})
+	reply, err = a.TrackByNumber(models.CarrierCodeFDXG, "794000000043")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := reply.CompletedTrackDetails[0].TrackDetails[0].Status(); status != models.TrackingStatusReturnedToShipper {
+		t.Fatal("should be returned to shipper, got", status)
+	}
+
+	server.SetTracking("794000000044", fedextest.Tracking{
+		CarrierCode: models.CarrierCodeFDXG,
+		Events: []fedextest.TrackingEvent{
+			{Timestamp: shipTime.Add(6 * time.Hour), EventType: "IP", EventDescription: "In FedEx possession"},
+			{Timestamp: shipTime.Add(2 * time.Hour), EventType: "AR", EventDescription: "Arrived at FedEx location"},
+			{Timestamp: shipTime, EventType: "PU", EventDescription: "Picked up"},
+		},
+	})
+	reply, err = a.TrackByNumber(models.CarrierCodeFDXG, "794000000044")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ship := reply.Ship(); ship == nil || !ship.Equal(shipTime) {
+		t.Fatal("ship should be the pickup, not the later hub scan, got", ship)
+	}
+}
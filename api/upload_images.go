@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/happyreturns/fedex/models"
@@ -11,12 +12,18 @@ const (
 )
 
 func (a API) UploadImages(images []models.Image) error {
+	return a.UploadImagesContext(context.Background(), images)
+}
+
+// UploadImagesContext is like UploadImages but aborts the request when ctx is
+// done
+func (a API) UploadImagesContext(ctx context.Context, images []models.Image) error {
 	endpoint := fmt.Sprintf("/uploaddocument/%s", uploadVersion)
 	request := a.uploadImagesRequest(images)
 	response := &models.UploadImagesResponseEnvelope{}
 
-	if err := a.makeRequestAndUnmarshalResponse(endpoint, request, response); err != nil {
-		return fmt.Errorf("make upload images request and unmarshal: %s", err)
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "UploadImages", endpoint, request, response); err != nil {
+		return fmt.Errorf("make upload images request and unmarshal: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+const (
+	validatePostalVersion = "v8"
+)
+
+// ValidatePostal validates the postal code of address with FedEx for
+// carrierCode, returning its normalized postal code, state or province and
+// city, and whether FedEx serves it. It fails when the postal code isn't in
+// the state or province of the address.
+func (a API) ValidatePostal(address models.Address, carrierCode string) (*models.PostalValidation, error) {
+	return a.ValidatePostalContext(context.Background(), address, carrierCode)
+}
+
+// ValidatePostalContext is like ValidatePostal but aborts the request when
+// ctx is done
+func (a API) ValidatePostalContext(ctx context.Context, address models.Address, carrierCode string) (*models.PostalValidation, error) {
+	endpoint := fmt.Sprintf("/cnty/%s", validatePostalVersion)
+	request := a.validatePostalRequest(address, carrierCode)
+	response := &models.ValidatePostalResponseEnvelope{}
+
+	if err := a.makeRequestAndUnmarshalResponse(ctx, "ValidatePostal", endpoint, request, response); err != nil {
+		return nil, fmt.Errorf("make validate postal request and unmarshal: %w", err)
+	}
+
+	validation := response.Reply.Validation(address)
+	return &validation, nil
+}
+
+func (a API) validatePostalRequest(address models.Address, carrierCode string) *models.Envelope {
+	return &models.Envelope{
+		Soapenv:   "http://schemas.xmlsoap.org/soap/envelope/",
+		Namespace: fmt.Sprintf("http://fedex.com/ws/cnty/%s", validatePostalVersion),
+		Body: models.ValidatePostalBody{
+			ValidatePostalRequest: models.ValidatePostalRequest{
+				Request: models.Request{
+					WebAuthenticationDetail: models.WebAuthenticationDetail{
+						UserCredential: models.UserCredential{
+							Key:      a.Key,
+							Password: a.Password,
+						},
+					},
+					ClientDetail: models.ClientDetail{
+						AccountNumber: a.Account,
+						MeterNumber:   a.Meter,
+					},
+					Version: models.Version{
+						ServiceID: "cnty",
+						Major:     8,
+					},
+				},
+				ShipDateTime:     models.Timestamp(time.Now()),
+				Address:          address,
+				CarrierCode:      carrierCode,
+				CheckForMismatch: true,
+			},
+		},
+	}
+}
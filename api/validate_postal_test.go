@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestValidatePostal(t *testing.T) {
+	server, a := newServer()
+	defer server.Close()
+
+	validation, err := a.ValidatePostal(fromAndTo.ToAddress, models.CarrierCodeFDXG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validation.PostalCode != "90401" || validation.StateOrProvinceCode != "CA" || validation.City != "SANTA MONICA" ||
+		validation.CountryCode != "US" || !validation.Serviceable || !validation.ExpressServiceable {
+		t.Fatal("should have the normalized and serviceable postal code", validation)
+	}
+
+	validation, err = a.ValidatePostal(models.Address{PostalCode: "96799", StateOrProvinceCode: "AS", CountryCode: "US"}, models.CarrierCodeFDXG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if validation.Serviceable {
+		t.Fatal("American Samoa should not be serviceable", validation)
+	}
+
+	mismatch := fromAndTo.ToAddress
+	mismatch.StateOrProvinceCode = "NY"
+	if _, err := a.ValidatePostal(mismatch, models.CarrierCodeFDXG); err == nil {
+		t.Fatal("postal code of another state should be invalid", err)
+	}
+}
@@ -0,0 +1,199 @@
+// Package cassette records FedEx requests and replies to disk, and replays
+// them, so a customer's bad label or odd track reply can be reproduced
+// offline. A Cassette is an http.RoundTripper, set it as API.Transport.
+//
+// Credentials, account numbers and contact PII are scrubbed with
+// api.RedactXML before anything is written to disk.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/happyreturns/fedex/api"
+)
+
+// Mode says whether a Cassette records or replays
+type Mode int
+
+const (
+	// ModeReplay serves recorded replies, and fails requests that weren't
+	// recorded
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to FedEx and records them with their replies
+	ModeRecord
+)
+
+// Interaction is a recorded request and its reply
+type Interaction struct {
+	Endpoint   string `json:"endpoint"`
+	Request    string `json:"request"`
+	StatusCode int    `json:"statusCode"`
+	Response   string `json:"response"`
+}
+
+// Cassette is a set of interactions
+type Cassette struct {
+	Path string
+	Mode Mode
+	// Transport sends requests in ModeRecord. http.DefaultTransport is used
+	// when nil.
+	Transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	played       map[int]bool
+}
+
+// New returns a Cassette recording to path. Call Save when done.
+func New(path string) *Cassette {
+	return &Cassette{Path: path, Mode: ModeRecord}
+}
+
+// Load returns a Cassette replaying the interactions recorded at path
+func Load(path string) (*Cassette, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+
+	c := &Cassette{Path: path, Mode: ModeReplay}
+	if err := json.Unmarshal(content, &c.interactions); err != nil {
+		return nil, fmt.Errorf("unmarshal cassette: %w", err)
+	}
+	return c, nil
+}
+
+// Interactions returns the interactions recorded or loaded so far
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction{}, c.interactions...)
+}
+
+// Save writes the recorded interactions to Path
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	content, err := json.MarshalIndent(c.interactions, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal cassette: %w", err)
+	}
+
+	if err := ioutil.WriteFile(c.Path, append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip records or replays req, depending on the mode
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+	}
+
+	if c.Mode == ModeRecord {
+		return c.record(req, body)
+	}
+	return c.replay(req, body)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	outReq := req.Clone(req.Context())
+	outReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp, err := transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, Interaction{
+		Endpoint:   req.URL.Path,
+		Request:    normalize(string(body)),
+		StatusCode: resp.StatusCode,
+		Response:   api.RedactXML(string(respBody)),
+	})
+	c.mu.Unlock()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// replay serves the first interaction matching req that hasn't been played
+// yet, or the last match if they all have, so retries see the same replies
+// they did when recording
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	request := normalize(string(body))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.played == nil {
+		c.played = map[int]bool{}
+	}
+
+	match := -1
+	for idx, interaction := range c.interactions {
+		if interaction.Endpoint != req.URL.Path || interaction.Request != request {
+			continue
+		}
+		match = idx
+		if !c.played[idx] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no interaction recorded for %s in %s", req.URL.Path, c.Path)
+	}
+	c.played[match] = true
+
+	interaction := c.interactions[match]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/xml;charset=UTF-8"}},
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Response)),
+		ContentLength: int64(len(interaction.Response)),
+		Request:       req,
+	}, nil
+}
+
+var (
+	// volatileRegex matches elements that change on every request, like the
+	// ship timestamp set from the current time
+	volatileRegex   = regexp.MustCompile(`<((?:[\w-]+:)?(?:ShipTimestamp|ReadyTimestamp|CompanyCloseTime|InEffectAsOfTimestamp|ShipDate|ShipDateRangeBegin|ShipDateRangeEnd|DispatchDate|CloseDate|TimeUpToWhichShipmentsAreToBeClosed))>[^<]*</`)
+	whitespaceRegex = regexp.MustCompile(`>\s+<`)
+)
+
+// normalize scrubs a request and removes what changes between two identical
+// requests, so that they can be matched
+func normalize(request string) string {
+	request = api.RedactXML(request)
+	request = volatileRegex.ReplaceAllString(request, "<$1>*</")
+	return whitespaceRegex.ReplaceAllString(strings.TrimSpace(request), "><")
+}
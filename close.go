@@ -0,0 +1,119 @@
+package fedex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// EndOfDayClose closes the ground shipments of the account shipped up to
+// closeTime and its SmartPost shipments when it has a hub ID, returning the
+// ground manifest and reports. Hub accounts only close ground shipments when
+// HubShipsGround is set. Having nothing to close isn't an error, and a failed
+// close doesn't stop the other one.
+func (f Fedex) EndOfDayClose(closeTime time.Time) ([]models.CloseReport, error) {
+	return f.EndOfDayCloseContext(context.Background(), closeTime)
+}
+
+// EndOfDayCloseContext is like EndOfDayClose but aborts the requests when ctx
+// is done
+func (f Fedex) EndOfDayCloseContext(ctx context.Context, closeTime time.Time) ([]models.CloseReport, error) {
+	var (
+		reports []models.CloseReport
+		errs    closeErrors
+	)
+	if !f.isSmartPost() || f.HubShipsGround {
+		var err error
+		reports, err = f.API.GroundCloseContext(ctx, closeTime)
+		if err != nil && !errors.Is(err, models.ErrNothingToClose) {
+			errs = append(errs, fmt.Errorf("ground close: %w", err))
+		}
+	}
+
+	if f.isSmartPost() {
+		err := f.API.SmartPostCloseContext(ctx)
+		if err != nil && !errors.Is(err, models.ErrNothingToClose) {
+			errs = append(errs, fmt.Errorf("smartpost close: %w", err))
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return reports, nil
+	case 1:
+		return reports, errs[0]
+	default:
+		return reports, errs
+	}
+}
+
+// closeErrors are the errors of both closes of an account
+type closeErrors []error
+
+func (e closeErrors) Error() string {
+	messages := make([]string, len(e))
+	for idx, err := range e {
+		messages[idx] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the errors is target
+func (e closeErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CloseError has the errors of the accounts CloseAccounts failed to close, by
+// account name
+type CloseError map[string]error
+
+func (e CloseError) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	messages := make([]string, len(names))
+	for idx, name := range names {
+		messages[idx] = fmt.Sprintf("%s: %s", name, e[name])
+	}
+	return fmt.Sprintf("close accounts: %s", strings.Join(messages, "; "))
+}
+
+// CloseAccounts closes the day of every account, keyed by name like in
+// creds.json, with EndOfDayClose. It returns the reports of the accounts it
+// closed, and a CloseError when it failed to close some.
+func CloseAccounts(accounts map[string]Fedex, closeTime time.Time) (map[string][]models.CloseReport, error) {
+	return CloseAccountsContext(context.Background(), accounts, closeTime)
+}
+
+// CloseAccountsContext is like CloseAccounts but aborts the requests when ctx
+// is done
+func CloseAccountsContext(ctx context.Context, accounts map[string]Fedex, closeTime time.Time) (map[string][]models.CloseReport, error) {
+	reports := map[string][]models.CloseReport{}
+	closeErr := CloseError{}
+	for name, account := range accounts {
+		accountReports, err := account.EndOfDayCloseContext(ctx, closeTime)
+		if err != nil {
+			closeErr[name] = err
+			continue
+		}
+		reports[name] = accountReports
+	}
+
+	if len(closeErr) > 0 {
+		return reports, closeErr
+	}
+	return reports, nil
+}
@@ -0,0 +1,76 @@
+package fedex
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestCloseAccounts(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+	ground := newFedex(server)
+	smartPost := newFedex(server)
+	smartPost.HubID = "5531"
+
+	if _, err := ground.Ship(&models.Shipment{FromAndTo: fromAndTo}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := smartPost.Ship(&models.Shipment{FromAndTo: fromAndTo, Service: "return"}); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts := map[string]Fedex{"ground": ground, "smartPost": smartPost}
+	reports, err := CloseAccounts(accounts, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || len(reports["ground"]) != 1 {
+		t.Fatal("should close every account, with the ground manifest", reports)
+	}
+	if err := smartPost.SmartPostClose(); !errors.Is(err, models.ErrNothingToClose) {
+		t.Fatal("smartpost packages should be closed", err)
+	}
+
+	server.FailNext(fedextest.EndpointClose, fedextest.FailureAuthentication)
+	_, err = CloseAccounts(accounts, time.Now())
+	closeErr := CloseError{}
+	if !errors.As(err, &closeErr) || len(closeErr) != 1 {
+		t.Fatal("should fail to close one account", err)
+	}
+}
+
+func TestEndOfDayCloseHubShipsGround(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+	f.HubID = "5531"
+
+	if _, err := f.EndOfDayClose(time.Now()); err != nil {
+		t.Fatal("hub accounts should only close smartpost", err)
+	}
+
+	f.HubShipsGround = true
+	if _, err := f.Ship(&models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Ship(&models.Shipment{FromAndTo: fromAndTo, Service: "return"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server.FailNext(fedextest.EndpointClose, fedextest.FailureAuthentication)
+	if _, err := f.EndOfDayClose(time.Now()); !errors.Is(err, models.ErrAuthFailure) {
+		t.Fatal("the ground close should fail", err)
+	}
+	if err := f.SmartPostClose(); !errors.Is(err, models.ErrNothingToClose) {
+		t.Fatal("smartpost packages should be closed even though the ground close failed", err)
+	}
+	if _, err := f.EndOfDayClose(time.Now()); err != nil {
+		t.Fatal("ground packages should be closed", err)
+	}
+	if _, err := f.GroundClose(time.Now()); !errors.Is(err, models.ErrNothingToClose) {
+		t.Fatal("ground packages should be closed", err)
+	}
+}
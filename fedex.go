@@ -4,15 +4,13 @@
 package fedex
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/happyreturns/fedex/api"
 	"github.com/happyreturns/fedex/models"
-	log "github.com/sirupsen/logrus"
 )
 
 // Convenience constants for standard Fedex API URLs
@@ -31,121 +29,130 @@ const (
 // Fedex WSDL docs here: http://images.fedex.com/us/developer/product/WebServices/MyWebHelp/DeveloperGuide2012.pdf
 type Fedex struct {
 	api.API
+
+	// PickupPolicy says when pickups are scheduled. DefaultPickupPolicy is
+	// used when nil.
+	PickupPolicy *PickupPolicy `json:"-"`
+	// CorrectResidential validates the addresses of shipments before shipping
+	// them, and ships them with their Residential flag corrected. The
+	// shipments passed to Ship are left as they are.
+	CorrectResidential bool `json:"-"`
+	// HubShipsGround says the account ships ground as well as SmartPost, so
+	// EndOfDayClose closes both. Other accounts with a hub ID only ship
+	// SmartPost.
+	HubShipsGround bool `json:"-"`
 }
 
-var laTimeZone *time.Location
+// CreatePickup creates a pickup on the first window of the pickup policy
+// FedEx accepts
+func (f Fedex) CreatePickup(pickup *models.Pickup) (*models.PickupSuccess, error) {
+	return f.CreatePickupContext(context.Background(), pickup)
+}
 
-func init() {
-	var err error
-	laTimeZone, err = time.LoadLocation("America/Los_Angeles")
-	if err != nil {
-		panic(err)
+// CreatePickupContext is like CreatePickup but stops retrying as soon as ctx
+// is done
+func (f Fedex) CreatePickupContext(ctx context.Context, pickup *models.Pickup) (*models.PickupSuccess, error) {
+	// Don't log the pickup contact or street, only where it is
+	address := pickup.PickupLocation.Address
+	fields := api.Fields{
+		"postalCode":  address.PostalCode,
+		"countryCode": address.CountryCode,
 	}
 
-	log.SetFormatter(&log.JSONFormatter{})
-	log.SetOutput(os.Stdout)
-}
+	now := time.Now()
+	policy := f.pickupPolicy()
+	windows, err := policy.Windows(address, now)
+	if err != nil {
+		return nil, fmt.Errorf("fedex create pickup: pickup windows: %w", err)
+	}
+	if policy.CheckAvailability {
+		windows = f.availableWindows(ctx, pickup.Carrier(), address, windows, now)
+	}
+	if len(windows) == 0 {
+		return nil, errors.New("fedex create pickup: no pickup window")
+	}
 
-// CreatePickup creates a pickup with retry logic to try pickups on the following days
-func (f Fedex) CreatePickup(pickup *models.Pickup) (*models.PickupSuccess, error) {
-	var (
-		reply *models.CreatePickupReply
-		err   error
-	)
-
-	for delay := 0; delay <= 5; delay++ {
-		fields := log.Fields{"pickup": pickup}
-
-		// Calculate pickup window, but just try the next window in case of error
-		window, err := pickupTimeWindow(pickup.PickupLocation.Address, delay)
-		if err != nil {
-			log.WithFields(fields).Error("calculate pickup time", err)
-			continue
+	for _, window := range windows {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("fedex create pickup: %w", ctx.Err())
 		}
+
+		window := window
 		fields["window"] = window
 
-		reply, err = f.API.CreatePickup(pickup, window)
-		switch err.(type) {
-		case nil:
-			fields["reply"] = reply
-			log.WithFields(fields).Info("made pickup")
+		var reply *models.CreatePickupReply
+		reply, err = f.API.CreatePickupContext(ctx, pickup, &window)
+		switch {
+		case err == nil:
+			fields["confirmationNumber"] = reply.PickupConfirmationNumber
+			f.EffectiveLogger().Info("made pickup", fields)
 			return &models.PickupSuccess{
 				ConfirmationNumber: reply.PickupConfirmationNumber,
-				Window:             *window,
+				Location:           reply.Location,
+				Window:             window,
 			}, nil
 
-		case models.PickupAlreadyExistsError:
-			fields["reply"] = reply
-			log.WithFields(fields).Info("pickup already exists")
+		case errors.Is(err, models.ErrPickupAlreadyExists):
+			f.EffectiveLogger().Info("pickup already exists", fields)
 			return &models.PickupSuccess{
-				Window: *window,
+				Window: window,
 			}, nil
 
 		default:
 			fields["err"] = err
-			log.WithFields(fields).Info("failed pickup")
+			f.EffectiveLogger().Info("failed pickup", fields)
 		}
 	}
 
-	return nil, fmt.Errorf("fedex create pickup: %s", err)
+	return nil, fmt.Errorf("fedex create pickup: %w", err)
 }
 
-func pickupTimeWindow(pickupAddress models.Address, numDaysToDelay int) (*models.PickupTimeWindow, error) {
-	location, err := toLocation(pickupAddress)
-	if err != nil {
-		location = laTimeZone
+// availableWindows returns the windows carrierCode can still pick up at
+// address when requested at now. When FedEx can't say, or says no window is
+// available, every window is returned.
+func (f Fedex) availableWindows(ctx context.Context, carrierCode string, address models.Address, windows []models.PickupTimeWindow, now time.Time) []models.PickupTimeWindow {
+	if len(windows) == 0 {
+		return windows
 	}
 
-	readyTime := time.Now().In(location).Add(time.Duration(numDaysToDelay*24) * time.Hour)
-
-	// If it's past the ready time of the current day, ship the next day, not today
-	if readyTime.After(timeForReadyPickup(readyTime)) {
-		readyTime = readyTime.Add(24 * time.Hour)
+	fields := api.Fields{
+		"postalCode":  address.PostalCode,
+		"countryCode": address.CountryCode,
 	}
-	readyTime = timeForReadyPickup(readyTime)
-
-	// Don't schedule pickups for Sunday
-	if readyTime.Weekday() == time.Sunday {
-		return nil, fmt.Errorf("no pickups on sunday %d", numDaysToDelay)
+	// Ask for the business days up to the last window, which the calendar
+	// days of the policy horizon don't tell across weekends
+	dispatchDate := windows[0].ReadyTime
+	availabilityOptions := api.PickupAvailabilityOptions{
+		BusinessDays: businessDaysUntil(dispatchDate, windows[len(windows)-1].ReadyTime),
+	}
+	reply, err := f.API.GetPickupAvailabilityWithOptionsContext(ctx, address, carrierCode, dispatchDate, availabilityOptions)
+	if err != nil {
+		fields["err"] = err
+		f.EffectiveLogger().Error("get pickup availability", fields)
+		return windows
 	}
 
-	return &models.PickupTimeWindow{
-		ReadyTime: readyTime,
-		CloseTime: readyTime.Add(8 * time.Hour),
-	}, nil
-}
-
-func timeForReadyPickup(t time.Time) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), 10, 45, 0, 0, t.Location())
+	available := filterAvailableWindows(windows, reply.AvailableOptions(), now)
+	if len(available) == 0 {
+		f.EffectiveLogger().Info("no available pickup window, trying every window", fields)
+		return windows
+	}
+	return available
 }
 
-// toLocation attempts to return the timezone based on state, returning los
-// angeles if unable to
-func toLocation(pickupAddress models.Address) (*time.Location, error) {
-	tzDatabaseName := ""
-	switch strings.ToUpper(pickupAddress.StateOrProvinceCode) {
-	case "AK":
-		tzDatabaseName = "America/Anchorage"
-	case "HI":
-		tzDatabaseName = "Pacific/Honolulu"
-	case "AL", "AR", "IL", "IA", "KS", "KY", "LA", "MN", "MS", "MO", "NE", "ND", "OK", "SD", "TN", "TX", "WI":
-		tzDatabaseName = "America/Chicago"
-	case "AZ", "CO", "ID", "MT", "NM", "UT", "WY":
-		tzDatabaseName = "America/Denver"
-	case "CT", "DC", "DE", "FL", "GA", "IN", "ME", "MD", "MA", "MI", "NH", "NJ", "NY", "NC", "OH", "PA", "RI", "SC", "VT", "VA", "WV":
-		tzDatabaseName = "America/New_York"
-	default:
-		return laTimeZone, nil
-	}
-
-	timeZone, err := time.LoadLocation(tzDatabaseName)
-	if err != nil {
-		return nil, fmt.Errorf("load location from time zone %s: %s", tzDatabaseName, err)
+func (f Fedex) pickupPolicy() PickupPolicy {
+	if f.PickupPolicy == nil {
+		return DefaultPickupPolicy
 	}
-	return timeZone, nil
+	return *f.PickupPolicy
 }
 
 func (f Fedex) Ship(shipment *models.Shipment) (*models.ProcessShipmentReply, error) {
+	return f.ShipContext(context.Background(), shipment)
+}
+
+// ShipContext is like Ship but aborts the request when ctx is done
+func (f Fedex) ShipContext(ctx context.Context, shipment *models.Shipment) (*models.ProcessShipmentReply, error) {
 	if f.isSmartPost() && shipment.IsInternational() {
 		return nil, errors.New("do not ship internationally with smartpost")
 	}
@@ -155,14 +162,42 @@ func (f Fedex) Ship(shipment *models.Shipment) (*models.ProcessShipmentReply, er
 		shipment.Service = "default"
 	}
 
-	reply, err := f.API.ProcessShipment(shipment)
+	if f.CorrectResidential {
+		shipment = f.correctResidential(ctx, shipment)
+	}
+
+	reply, err := f.API.ProcessShipmentContext(ctx, shipment)
 	if err != nil {
-		return nil, fmt.Errorf("api process shipment: %s", err)
+		// reply has the packages shipped so far when they couldn't be deleted
+		return reply, fmt.Errorf("api process shipment: %w", err)
 	}
 
 	return reply, nil
 }
 
+// correctResidential returns a copy of shipment with the Residential flag set
+// on the addresses FedEx classifies as business or residential. Shipping goes
+// on with the flags as they were when validation fails.
+func (f Fedex) correctResidential(ctx context.Context, shipment *models.Shipment) *models.Shipment {
+	corrected := *shipment
+	addresses := []*models.Address{&corrected.FromAddress, &corrected.ToAddress}
+	results, err := f.API.ValidateAddressesContext(ctx, []models.Address{*addresses[0], *addresses[1]})
+	if err != nil {
+		f.EffectiveLogger().Error("validate shipment addresses", api.Fields{"err": err})
+		return shipment
+	}
+
+	for idx, result := range results {
+		switch result.Classification {
+		case models.AddressClassificationBusiness:
+			addresses[idx].Residential = false
+		case models.AddressClassificationResidential:
+			addresses[idx].Residential = true
+		}
+	}
+	return &corrected
+}
+
 func (f Fedex) isSmartPost() bool {
 	return f.API.HubID != ""
 }
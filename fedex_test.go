@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/happyreturns/fedex/api"
+	"github.com/happyreturns/fedex/fedextest"
 	"github.com/happyreturns/fedex/models"
 )
 
@@ -20,6 +22,16 @@ var (
 	blandonSmartPostFedex Fedex
 )
 
+var laTimeZone *time.Location
+
+func init() {
+	var err error
+	laTimeZone, err = time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		panic(err)
+	}
+}
+
 func TestMain(m *testing.M) {
 	credData, err := ioutil.ReadFile("creds.json")
 	if err != nil {
@@ -728,3 +740,185 @@ func checkErrorMatches(t *testing.T, err error, expectedText string) {
 		t.Fatal("error", err, "doesn't match", expectedText)
 	}
 }
+
+var fromAndTo = models.FromAndTo{
+	FromAddress: models.Address{
+		StreetLines:         []string{"1517 Lincoln Blvd"},
+		City:                "Santa Monica",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	},
+	ToAddress: models.Address{
+		StreetLines:         []string{"1106 Broadway"},
+		City:                "Santa Monica",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	},
+	FromContact: models.Contact{PersonName: "Joe Customer", PhoneNumber: "2135550000"},
+	ToContact:   models.Contact{CompanyName: "Happy Returns", PhoneNumber: "4243259510"},
+}
+
+// newServer starts a fake server and returns a client of it. Close the server
+// when done.
+func newServer() (*fedextest.Server, Fedex) {
+	server := fedextest.NewServer()
+	return server, newFedex(server)
+}
+
+func newFedex(server *fedextest.Server) Fedex {
+	return Fedex{API: api.API{
+		Key:         "key",
+		Password:    "password",
+		Account:     "account",
+		Meter:       "meter",
+		FedExURL:    server.URL,
+		Logger:      api.NopLogger{},
+		RetryPolicy: &api.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}}
+}
+
+func TestShipCorrectResidential(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+	server.SetAddressClassification("1106 Broadway", models.AddressClassificationBusiness)
+	server.SetAddressClassification("1517 Lincoln Blvd", models.AddressClassificationResidential)
+
+	shipment := &models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground"}
+	shipment.ToAddress.Residential = true
+	f.CorrectResidential = true
+	if _, err := f.Ship(shipment); err != nil {
+		t.Fatal(err)
+	}
+	if shipment.FromAddress.Residential || !shipment.ToAddress.Residential {
+		t.Fatal("the shipment should be left as it is", shipment.FromAddress.Residential, shipment.ToAddress.Residential)
+	}
+
+	var shipRequest string
+	for _, request := range server.Requests() {
+		if strings.Contains(request.Body, "ProcessShipmentRequest") {
+			shipRequest = request.Body
+		}
+	}
+	recipient := strings.Index(shipRequest, "<q0:Recipient>")
+	if recipient < 0 || !strings.Contains(shipRequest[:recipient], "<q0:Residential>1</q0:Residential>") ||
+		!strings.Contains(shipRequest[recipient:], "<q0:Residential>0</q0:Residential>") {
+		t.Fatal("residential flags should be corrected", shipRequest)
+	}
+}
+
+func TestPickupAlreadyExists(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+
+	pickup := &models.Pickup{
+		PickupLocation: models.PickupLocation{Address: fromAndTo.FromAddress, Contact: fromAndTo.FromContact},
+		ToAddress:      fromAndTo.ToAddress,
+	}
+
+	first, err := f.CreatePickup(pickup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ConfirmationNumber == "" {
+		t.Fatal("first pickup should have a confirmation number")
+	}
+
+	second, err := f.CreatePickup(pickup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ConfirmationNumber != "" || !second.Window.ReadyTime.Equal(first.Window.ReadyTime) {
+		t.Fatal("second pickup should already exist")
+	}
+}
+
+func TestCancelPickup(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+
+	pickup := &models.Pickup{
+		PickupLocation: models.PickupLocation{Address: fromAndTo.FromAddress, Contact: fromAndTo.FromContact},
+		ToAddress:      fromAndTo.ToAddress,
+	}
+	success, err := f.CreatePickup(pickup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if success.Location == "" {
+		t.Fatal("pickup should have a location")
+	}
+
+	err = f.CancelPickup(CarrierCodeGround, success.ConfirmationNumber, success.Window.ReadyTime, success.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.CancelPickup(CarrierCodeGround, success.ConfirmationNumber, success.Window.ReadyTime, success.Location)
+	if err == nil {
+		t.Fatal("pickup should only be cancelled once")
+	}
+
+	again, err := f.CreatePickup(pickup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ConfirmationNumber == "" || again.ConfirmationNumber == success.ConfirmationNumber {
+		t.Fatal("pickup should be created again once cancelled", again.ConfirmationNumber)
+	}
+}
+
+func TestCreatePickupCheckingAvailability(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+	f.PickupPolicy = &PickupPolicy{
+		Hours:             DefaultPickupPolicy.Hours,
+		Horizon:           6,
+		CheckAvailability: true,
+	}
+
+	success, err := f.CreatePickup(&models.Pickup{
+		PickupLocation: models.PickupLocation{Address: fromAndTo.FromAddress, Contact: fromAndTo.FromContact},
+		ToAddress:      fromAndTo.ToAddress,
+		CarrierCode:    CarrierCodeSmartPost,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weekday := success.Window.ReadyTime.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+		t.Fatal("pickups should only be on available days, got", weekday)
+	}
+
+	var availabilityRequest string
+	for _, request := range server.Requests() {
+		if strings.Contains(request.Body, "GetPickupAvailabilityRequest") {
+			availabilityRequest = request.Body
+		}
+	}
+	if !strings.Contains(availabilityRequest, "<q0:Carriers>FXSP</q0:Carriers>") {
+		t.Fatal("availability should be asked for the carrier of the pickup", availabilityRequest)
+	}
+}
+
+func TestCreatePickupWithoutAvailableWindow(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+	// FedEx never says weekends are available
+	f.PickupPolicy = &PickupPolicy{
+		Hours:             DefaultPickupPolicy.Hours,
+		BlockedWeekdays:   []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Horizon:           6,
+		CheckAvailability: true,
+	}
+
+	success, err := f.CreatePickup(&models.Pickup{
+		PickupLocation: models.PickupLocation{Address: fromAndTo.FromAddress, Contact: fromAndTo.FromContact},
+		ToAddress:      fromAndTo.ToAddress,
+	})
+	if err != nil {
+		t.Fatal("should fall back on every window", err)
+	}
+	if weekday := success.Window.ReadyTime.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+		t.Fatal("pickups should be on a window of the policy, got", weekday)
+	}
+}
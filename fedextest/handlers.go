@@ -0,0 +1,1162 @@
+package fedextest
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// labelPNG is a 1x1 PNG returned for every label and document
+var labelPNG = base64.StdEncoding.EncodeToString([]byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d,
+	0x49, 0x48, 0x44, 0x52, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01,
+	0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4, 0x89, 0x00, 0x00, 0x00,
+	0x0d, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x63, 0x60, 0x00, 0x02, 0x00,
+	0x00, 0x05, 0x00, 0x01, 0xe9, 0xfa, 0xdc, 0xd8, 0x00, 0x00, 0x00, 0x00,
+	0x49, 0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+})
+
+// rateServices are the services quoted when rate shopping, with how many
+// times the ground price they cost and their business days in transit.
+// Express services commit to a delivery time.
+var rateServices = []struct {
+	ServiceType string
+	Multiplier  float64
+	TransitDays int
+	Commit      bool
+}{
+	{"FEDEX_GROUND", 1, 4, false},
+	{"FEDEX_EXPRESS_SAVER", 2, 3, true},
+	{"FEDEX_2_DAY", 3, 2, true},
+	{"STANDARD_OVERNIGHT", 5, 1, true},
+}
+
+var transitTimes = []string{"ONE_DAY", "TWO_DAYS", "THREE_DAYS", "FOUR_DAYS", "FIVE_DAYS"}
+
+// rate quotes a made up price that grows with the weight of the packages.
+// Without a service type, every service in rateServices is quoted.
+func (s *Server) rate(body []byte) (reply, error) {
+	request := rateRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal rate request: %s", err)
+	}
+
+	shipment := request.RequestedShipment
+	totalWeight := 0.0
+	for _, item := range shipment.RequestedPackageLineItems {
+		totalWeight += item.Weight.Value
+	}
+	groundCharge := 8.5 + 0.9*totalWeight
+
+	reply := &rateReply{
+		replyHeader: successHeader(namespaceRate, "RateReply", "crs", 24),
+	}
+	for _, service := range rateServices {
+		if shipment.ServiceType != "" && shipment.ServiceType != service.ServiceType {
+			continue
+		}
+
+		baseCharge := math.Round(groundCharge*service.Multiplier*100) / 100
+		surcharges := 1.25
+		total := charge{Currency: "USD", Amount: baseCharge + surcharges}
+
+		detail := rateReplyDetail{
+			ServiceType:    service.ServiceType,
+			PackagingType:  "YOUR_PACKAGING",
+			ActualRateType: "PAYOR_ACCOUNT_PACKAGE",
+			RatedShipmentDetails: []ratedShipmentDetail{{
+				ShipmentRateDetail: rateDetail{
+					RateType:                         "PAYOR_ACCOUNT_PACKAGE",
+					TotalBillingWeight:               weight{Units: "LB", Value: math.Ceil(totalWeight)},
+					TotalBaseCharge:                  charge{Currency: "USD", Amount: baseCharge},
+					TotalSurcharges:                  charge{Currency: "USD", Amount: surcharges},
+					TotalNetCharge:                   total,
+					TotalNetChargeWithDutiesAndTaxes: total,
+				},
+			}},
+		}
+		if request.ReturnTransitAndCommit {
+			detail.IneligibleForMoneyBackGuarantee = !service.Commit
+			if service.Commit {
+				commit := businessDaysAfter(s.now(), service.TransitDays)
+				commit = time.Date(commit.Year(), commit.Month(), commit.Day(), 16, 30, 0, 0, commit.Location())
+				detail.DeliveryTimestamp = timestamp(commit)
+			} else {
+				detail.TransitTime = transitTimes[service.TransitDays-1]
+			}
+		}
+		reply.RateReplyDetails = append(reply.RateReplyDetails, detail)
+	}
+
+	if len(reply.RateReplyDetails) == 0 {
+		return failedReply(reply, Failure{Code: "556", Message: "There are no valid services available."}), nil
+	}
+	return reply, nil
+}
+
+func businessDaysAfter(t time.Time, days int) time.Time {
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return t
+}
+
+func (s *Server) shipService(body []byte) (reply, error) {
+	name, err := requestName(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "ProcessShipmentRequest":
+		return s.ship(body)
+	case "DeleteShipmentRequest":
+		return s.deleteShipment(body)
+	default:
+		return nil, fmt.Errorf("unsupported ship service request %s", name)
+	}
+}
+
+// ship creates a label, and starts tracking the package as label created
+func (s *Server) ship(body []byte) (reply, error) {
+	request := processShipmentRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal process shipment request: %s", err)
+	}
+	shipment := request.RequestedShipment
+
+	carrierCode, trackingIDType := "FDXG", "GROUND"
+	if shipment.ServiceType == "SMART_POST" {
+		carrierCode, trackingIDType = "FXSP", "USPS"
+	} else if strings.HasPrefix(shipment.ServiceType, "INTERNATIONAL_") {
+		carrierCode, trackingIDType = "FDXE", "EXPRESS"
+	}
+
+	reply := &processShipmentReply{
+		replyHeader: successHeader(namespaceShip, "ProcessShipmentReply", "ship", 23),
+	}
+	sequenceNumber := 1
+	if len(shipment.RequestedPackageLineItems) > 0 {
+		sequenceNumber = shipment.RequestedPackageLineItems[0].SequenceNumber
+	}
+
+	s.mu.Lock()
+	// Child packages of a multi-piece shipment have the tracking number of
+	// the master package
+	masterTrackingNumber := ""
+	if shipment.MasterTrackingID != nil {
+		masterTrackingNumber = shipment.MasterTrackingID.TrackingNumber
+		if _, ok := s.tracking[masterTrackingNumber]; !ok {
+			s.mu.Unlock()
+			return failedReply(reply, Failure{Code: "8245", Message: "Master tracking number is invalid."}), nil
+		}
+	}
+	trackingNumber := s.newTrackingNumber(carrierCode)
+	now := s.now()
+	s.tracking[trackingNumber] = Tracking{
+		CarrierCode:            carrierCode,
+		ServiceType:            shipment.ServiceType,
+		ShipTime:               now,
+		MasterTrackingNumber:   masterTrackingNumber,
+		References:             shipmentReferences(shipment),
+		DestinationPostalCode:  shipment.Recipient.Address.PostalCode,
+		DestinationCountryCode: shipment.Recipient.Address.CountryCode,
+		Events: []TrackingEvent{{
+			Timestamp:           now,
+			EventType:           "OC",
+			EventDescription:    "Shipment information sent to FedEx",
+			City:                shipment.Shipper.Address.City,
+			StateOrProvinceCode: shipment.Shipper.Address.StateOrProvinceCode,
+			PostalCode:          shipment.Shipper.Address.PostalCode,
+			CountryCode:         shipment.Shipper.Address.CountryCode,
+		}},
+	}
+	s.mu.Unlock()
+
+	if masterTrackingNumber == "" {
+		masterTrackingNumber = trackingNumber
+	}
+	imageType := shipment.LabelSpecification.ImageType
+	if imageType == "" {
+		imageType = "PNG"
+	}
+	id := trackingID{TrackingIDType: trackingIDType, TrackingNumber: trackingNumber}
+	masterID := trackingID{TrackingIDType: trackingIDType, TrackingNumber: masterTrackingNumber}
+
+	detail := &reply.CompletedShipmentDetail
+	detail.UsDomestic = shipment.Shipper.Address.CountryCode == shipment.Recipient.Address.CountryCode
+	detail.CarrierCode = carrierCode
+	detail.MasterTrackingID = masterID
+	detail.ServiceTypeDescription = shipment.ServiceType
+	detail.CompletedPackageDetails.SequenceNumber = sequenceNumber
+	detail.CompletedPackageDetails.TrackingIds = []trackingID{id}
+	detail.CompletedPackageDetails.Label = shippingDocument{
+		Type:                        "OUTBOUND_LABEL",
+		ShippingDocumentDisposition: "RETURNED",
+		ImageType:                   imageType,
+		Resolution:                  200,
+		CopiesToPrint:               1,
+		Parts:                       []documentPart{{DocumentPartSequenceNumber: 1, Image: labelPNG}},
+	}
+	for _, documentType := range shipment.ShippingDocumentSpecification.ShippingDocumentTypes {
+		detail.ShipmentDocuments = append(detail.ShipmentDocuments, shippingDocument{
+			Type:                        documentType,
+			ShippingDocumentDisposition: "RETURNED",
+			ImageType:                   "PDF",
+			Resolution:                  200,
+			CopiesToPrint:               1,
+			Parts:                       []documentPart{{DocumentPartSequenceNumber: 1, Image: labelPNG}},
+		})
+	}
+
+	return reply, nil
+}
+
+// deleteShipment cancels a package, or every package of its multi-piece
+// shipment, unless one was picked up already
+func (s *Server) deleteShipment(body []byte) (reply, error) {
+	request := deleteShipmentRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal delete shipment request: %s", err)
+	}
+
+	reply := &shipmentReply{
+		replyHeader: successHeader(namespaceShip, "ShipmentReply", "ship", 23),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trackingNumber := request.TrackingID.TrackingNumber
+	tracking, ok := s.tracking[trackingNumber]
+	if !ok {
+		return failedReply(reply, Failure{Code: "8149", Message: "Unable to retrieve record from database."}), nil
+	}
+
+	if isCancelled(tracking) {
+		return failedReply(reply, Failure{Code: "8159", Message: "Shipment Delete was requested for a tracking number already in a deleted state."}), nil
+	}
+
+	trackingNumbers := []string{trackingNumber}
+	if request.DeletionControl == "DELETE_ALL_PACKAGES" {
+		masterTrackingNumber := tracking.MasterTrackingNumber
+		if masterTrackingNumber == "" {
+			masterTrackingNumber = trackingNumber
+		}
+		trackingNumbers = nil
+		for number, other := range s.tracking {
+			isPackage := number == masterTrackingNumber || other.MasterTrackingNumber == masterTrackingNumber
+			if isPackage && !isCancelled(other) {
+				trackingNumbers = append(trackingNumbers, number)
+			}
+		}
+	}
+
+	for _, number := range trackingNumbers {
+		if events := s.tracking[number].Events; len(events) > 1 || (len(events) == 1 && events[0].EventType != "OC") {
+			return failedReply(reply, Failure{Code: "8160", Message: "Unable to delete shipment, it has already been tendered to FedEx."}), nil
+		}
+	}
+
+	now := s.now()
+	for _, number := range trackingNumbers {
+		tracking := s.tracking[number]
+		tracking.Events = append([]TrackingEvent{{
+			Timestamp:        now,
+			EventType:        "CA",
+			EventDescription: "Shipment cancelled by sender",
+		}}, tracking.Events...)
+		s.tracking[number] = tracking
+	}
+	return reply, nil
+}
+
+func isCancelled(tracking Tracking) bool {
+	return len(tracking.Events) > 0 && tracking.Events[0].EventType == "CA"
+}
+
+// customerReferenceTypes maps the customer reference types of a shipment to
+// the package identifier types they can be tracked by
+var customerReferenceTypes = map[string]string{
+	"CUSTOMER_REFERENCE": "CUSTOMER_REFERENCE",
+	"INVOICE_NUMBER":     "INVOICE",
+	"P_O_NUMBER":         "PURCHASE_ORDER",
+	"RMA_ASSOCIATION":    "RMA",
+}
+
+func shipmentReferences(shipment requestedShipment) []Reference {
+	var references []Reference
+	for _, item := range shipment.RequestedPackageLineItems {
+		for _, customerReference := range item.CustomerReferences {
+			if identifierType, ok := customerReferenceTypes[customerReference.CustomerReferenceType]; ok {
+				references = append(references, Reference{Type: identifierType, Value: customerReference.Value})
+			}
+		}
+	}
+	return references
+}
+
+// newTrackingNumber returns a tracking number that looks like one from
+// carrierCode. s.mu must be held.
+func (s *Server) newTrackingNumber(carrierCode string) string {
+	n := s.nextTrackingNumber
+	s.nextTrackingNumber++
+
+	if carrierCode == "FXSP" {
+		return fmt.Sprintf("6129099882%012d", n)
+	}
+	return fmt.Sprintf("7940%08d", n)
+}
+
+func (s *Server) track(body []byte) (reply, error) {
+	request := trackRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal track request: %s", err)
+	}
+
+	reply := &trackReply{
+		replyHeader: successHeader(namespaceTrack, "TrackReply", "trck", 16),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, selection := range request.SelectionDetails {
+		var completed completedTrackDetail
+		if selection.PackageIdentifier.Type == "TRACKING_NUMBER_OR_DOORTAG" {
+			completed = s.trackNumber(selection)
+		} else {
+			completed = s.trackReference(selection)
+		}
+
+		completed, err := s.page(completed, selection)
+		if err != nil {
+			return nil, err
+		}
+		reply.CompletedTrackDetails = append(reply.CompletedTrackDetails, completed)
+	}
+	return reply, nil
+}
+
+// trackNumber returns the package with the tracking number of selection, or
+// the candidates if it's a duplicate waybill. s.mu must be held.
+func (s *Server) trackNumber(selection selectionDetails) completedTrackDetail {
+	trackingNumber := selection.PackageIdentifier.Value
+	completed := completedTrackDetail{
+		HighestSeverity: "SUCCESS",
+		Notifications:   successHeader("", "", "trck", 16).Notifications,
+	}
+
+	if candidates, ok := s.duplicates[trackingNumber]; ok {
+		for _, candidate := range candidates {
+			detail := newTrackDetail(trackingNumber, candidate)
+			if selection.TrackingNumberUniqueIdentifier == detail.TrackingNumberUniqueIdentifier {
+				completed.TrackDetails = []trackDetail{detail}
+				return completed
+			}
+			completed.TrackDetails = append(completed.TrackDetails, detail)
+		}
+		if selection.TrackingNumberUniqueIdentifier != "" {
+			return notFoundTrackDetail(trackingNumber)
+		}
+		completed.DuplicateWaybill = true
+		return completed
+	}
+
+	tracking, ok := s.tracking[trackingNumber]
+	if !ok {
+		return notFoundTrackDetail(trackingNumber)
+	}
+	completed.TrackDetails = []trackDetail{newTrackDetail(trackingNumber, tracking)}
+	return completed
+}
+
+// page returns the page of completed requested by selection, with
+// s.trackPageSize track details per page. s.mu must be held.
+func (s *Server) page(completed completedTrackDetail, selection selectionDetails) (completedTrackDetail, error) {
+	if s.trackPageSize <= 0 {
+		return completed, nil
+	}
+
+	start := 0
+	if selection.PagingDetail != nil && selection.PagingDetail.PagingToken != "" {
+		var err error
+		start, err = strconv.Atoi(selection.PagingDetail.PagingToken)
+		if err != nil || start < 0 || start > len(completed.TrackDetails) {
+			return completed, fmt.Errorf("invalid paging token %q", selection.PagingDetail.PagingToken)
+		}
+	}
+
+	completed.TrackDetailsCount = len(completed.TrackDetails)
+	end := start + s.trackPageSize
+	if end < len(completed.TrackDetails) {
+		completed.MoreData = true
+		completed.PagingToken = strconv.Itoa(end)
+	} else {
+		end = len(completed.TrackDetails)
+	}
+	completed.TrackDetails = completed.TrackDetails[start:end]
+	return completed, nil
+}
+
+// trackReference returns the packages matching a selection by reference.
+// s.mu must be held.
+func (s *Server) trackReference(selection selectionDetails) completedTrackDetail {
+	trackingNumbers := []string{}
+	for trackingNumber, tracking := range s.tracking {
+		if referenceMatches(selection, tracking) {
+			trackingNumbers = append(trackingNumbers, trackingNumber)
+		}
+	}
+	if len(trackingNumbers) == 0 {
+		return notFoundTrackDetail(selection.PackageIdentifier.Value)
+	}
+	sort.Strings(trackingNumbers)
+
+	completed := completedTrackDetail{
+		HighestSeverity: "SUCCESS",
+		Notifications:   successHeader("", "", "trck", 16).Notifications,
+	}
+	for _, trackingNumber := range trackingNumbers {
+		completed.TrackDetails = append(completed.TrackDetails, newTrackDetail(trackingNumber, s.tracking[trackingNumber]))
+	}
+	return completed
+}
+
+func referenceMatches(selection selectionDetails, tracking Tracking) bool {
+	if selection.CarrierCode != "" && selection.CarrierCode != tracking.CarrierCode {
+		return false
+	}
+	if destination := selection.Destination; destination != nil {
+		if destination.CountryCode != tracking.DestinationCountryCode ||
+			(destination.PostalCode != "" && destination.PostalCode != tracking.DestinationPostalCode) {
+			return false
+		}
+	}
+
+	shipDate := tracking.ShipTime.Format("2006-01-02")
+	if (selection.ShipDateRangeBegin != "" && shipDate < selection.ShipDateRangeBegin) ||
+		(selection.ShipDateRangeEnd != "" && shipDate > selection.ShipDateRangeEnd) {
+		return false
+	}
+
+	for _, reference := range tracking.References {
+		if reference.Type == selection.PackageIdentifier.Type && reference.Value == selection.PackageIdentifier.Value {
+			return true
+		}
+	}
+	return false
+}
+
+func notFoundTrackDetail(trackingNumber string) completedTrackDetail {
+	message := "This tracking number cannot be found. Please check the number or contact the sender."
+	return completedTrackDetail{
+		HighestSeverity: "SUCCESS",
+		Notifications:   successHeader("", "", "trck", 16).Notifications,
+		TrackDetails: []trackDetail{{
+			Notification: notification{
+				Severity:         "ERROR",
+				Source:           "trck",
+				Code:             "9040",
+				Message:          message,
+				LocalizedMessage: message,
+			},
+			TrackingNumber: trackingNumber,
+		}},
+	}
+}
+
+// uniqueIdentifier tells apart the shipments with the same tracking number by
+// their ship date
+func uniqueIdentifier(trackingNumber string, tracking Tracking) string {
+	prefix := "12019"
+	if !tracking.ShipTime.IsZero() {
+		prefix = tracking.ShipTime.Format("20060102")
+	}
+	return fmt.Sprintf("%s~%s~%s", prefix, trackingNumber, tracking.CarrierCode)
+}
+
+func newTrackDetail(trackingNumber string, tracking Tracking) trackDetail {
+	detail := trackDetail{
+		Notification: notification{
+			Severity:         "SUCCESS",
+			Source:           "trck",
+			Code:             "0",
+			Message:          "Request was successfully processed.",
+			LocalizedMessage: "Request was successfully processed.",
+		},
+		TrackingNumber:                       trackingNumber,
+		TrackingNumberUniqueIdentifier:       uniqueIdentifier(trackingNumber, tracking),
+		CarrierCode:                          tracking.CarrierCode,
+		OperatingCompanyOrCarrierDescription: carrierDescription(tracking.CarrierCode),
+		DeliverySignatureName:                tracking.DeliverySignatureName,
+	}
+	if tracking.ServiceType != "" {
+		detail.Service = &service{Type: tracking.ServiceType}
+	}
+
+	if len(tracking.Events) > 0 {
+		latest := tracking.Events[0]
+		detail.StatusDetail = &statusDetail{
+			CreationTime: timestamp(latest.Timestamp),
+			Code:         latest.EventType,
+			Description:  latest.EventDescription,
+		}
+	}
+
+	datesOrTimes := []struct {
+		Type string
+		Time time.Time
+	}{
+		{"ACTUAL_DELIVERY", tracking.ActualDelivery},
+		{"ESTIMATED_DELIVERY", tracking.EstimatedDelivery},
+		{"SHIP", tracking.ShipTime},
+	}
+	for _, dateOrTime := range datesOrTimes {
+		if dateOrTime.Time.IsZero() {
+			continue
+		}
+		detail.DatesOrTimes = append(detail.DatesOrTimes, dateOrTimestamp{
+			Type:            dateOrTime.Type,
+			DateOrTimestamp: timestamp(dateOrTime.Time),
+		})
+	}
+
+	for _, event := range tracking.Events {
+		detail.Events = append(detail.Events, trackEvent{
+			Timestamp:        timestamp(event.Timestamp),
+			EventType:        event.EventType,
+			EventDescription: event.EventDescription,
+			Address: address{
+				City:                event.City,
+				StateOrProvinceCode: event.StateOrProvinceCode,
+				PostalCode:          event.PostalCode,
+				CountryCode:         event.CountryCode,
+			},
+		})
+	}
+
+	return detail
+}
+
+func carrierDescription(carrierCode string) string {
+	switch carrierCode {
+	case "FDXE":
+		return "FedEx Express"
+	case "FXSP":
+		return "FedEx SmartPost"
+	default:
+		return "FedEx Ground"
+	}
+}
+
+// trackService serves the operations of the Track service at
+// EndpointSendNotifications
+func (s *Server) trackService(body []byte) (reply, error) {
+	name, err := requestName(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "SendNotificationsRequest":
+		return s.sendNotifications(body)
+	case "GetTrackingDocumentsRequest":
+		return s.getTrackingDocuments(body)
+	default:
+		return nil, fmt.Errorf("unsupported track service request %s", name)
+	}
+}
+
+func (s *Server) sendNotifications(body []byte) (reply, error) {
+	request := sendNotificationsRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal send notifications request: %s", err)
+	}
+
+	s.mu.Lock()
+	tracking, ok := s.tracking[request.TrackingNumber]
+	s.mu.Unlock()
+
+	reply := &sendNotificationsReply{
+		replyHeader: successHeader(namespaceTrack, "SendNotificationsReply", "trck", 16),
+	}
+	if !ok {
+		return failedReply(reply, Failure{Code: "9085", Message: "Invalid tracking numbers."}), nil
+	}
+	reply.Packages = append(reply.Packages, notificationPackage{
+		TrackingNumber: request.TrackingNumber,
+		CarrierCode:    tracking.CarrierCode,
+	})
+	return reply, nil
+}
+
+// spodPDF is the PDF returned for every signature proof of delivery letter
+var spodPDF = base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n%%EOF\n"))
+
+// getTrackingDocuments returns signature proofs of delivery of delivered
+// packages
+func (s *Server) getTrackingDocuments(body []byte) (reply, error) {
+	request := getTrackingDocumentsRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal get tracking documents request: %s", err)
+	}
+
+	reply := &getTrackingDocumentsReply{
+		replyHeader: successHeader(namespaceTrack, "GetTrackingDocumentsReply", "trck", 16),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, selection := range request.SelectionDetails {
+		tracking, ok := s.tracking[selection.PackageIdentifier.Value]
+		if !ok || tracking.ActualDelivery.IsZero() {
+			return failedReply(reply, Failure{
+				Code:    "6115",
+				Message: "Signature Proof of Delivery is not currently available for this Tracking Number.",
+			}), nil
+		}
+
+		imageType, image := request.ImageType, labelPNG
+		if imageType != "PNG" {
+			imageType, image = "PDF", spodPDF
+		}
+		reply.Documents = append(reply.Documents, trackingDocument{
+			DocumentType: "SIGNATURE_PROOF_OF_DELIVERY",
+			ImageType:    imageType,
+			Images:       []string{image},
+		})
+	}
+	return reply, nil
+}
+
+// pickup creates one pickup per location and day. Later pickups for the same
+// location and day fail like FedEx does.
+func (s *Server) pickupService(body []byte) (reply, error) {
+	name, err := requestName(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "CreatePickupRequest":
+		return s.createPickup(body)
+	case "CancelPickupRequest":
+		return s.cancelPickup(body)
+	case "GetPickupAvailabilityRequest":
+		return s.getPickupAvailability(body)
+	default:
+		return nil, fmt.Errorf("unsupported pickup service request %s", name)
+	}
+}
+
+// pickupCutOff and pickupAccessTime are the cut off and access times of
+// every pickup day
+const (
+	pickupCutOff     = "15:00:00"
+	pickupAccessTime = "PT2H0M"
+)
+
+func (s *Server) createPickup(body []byte) (reply, error) {
+	request := createPickupRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal create pickup request: %s", err)
+	}
+
+	origin := request.OriginDetail
+	day := origin.ReadyTimestamp
+	if len(day) > len("2006-01-02") {
+		day = day[:len("2006-01-02")]
+	}
+	key := strings.Join(append(origin.PickupLocation.Address.StreetLines, origin.PickupLocation.Address.PostalCode, day), "|")
+
+	reply := &createPickupReply{
+		replyHeader: successHeader(namespacePickup, "CreatePickupReply", "disp", 17),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.pickups[key]; exists {
+		return failedReply(reply, Failure{Code: "9431", Message: "A pickup already exists for this location and day."}), nil
+	}
+	s.numPickups++
+	reply.PickupConfirmationNumber = fmt.Sprintf("%d", 1000+s.numPickups)
+	reply.Location = "SMOA"
+	s.pickups[key] = reply.PickupConfirmationNumber
+	return reply, nil
+}
+
+// cancelPickup cancels the pickup of a confirmation number on its day
+func (s *Server) cancelPickup(body []byte) (reply, error) {
+	request := cancelPickupRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal cancel pickup request: %s", err)
+	}
+
+	reply := &cancelPickupReply{
+		replyHeader: successHeader(namespacePickup, "CancelPickupReply", "disp", 17),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, confirmationNumber := range s.pickups {
+		if confirmationNumber == request.PickupConfirmationNumber && strings.HasSuffix(key, "|"+request.ScheduledDate) {
+			delete(s.pickups, key)
+			return reply, nil
+		}
+	}
+	return failedReply(reply, Failure{Code: "9435", Message: "Unable to cancel pickup. No pickup found for the confirmation number and date."}), nil
+}
+
+// getPickupAvailability makes weekdays available, except today after the
+// cut off
+func (s *Server) getPickupAvailability(body []byte) (reply, error) {
+	request := getPickupAvailabilityRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal get pickup availability request: %s", err)
+	}
+
+	now := s.now()
+	reply := &getPickupAvailabilityReply{
+		replyHeader:      successHeader(namespacePickup, "GetPickupAvailabilityReply", "disp", 17),
+		RequestTimestamp: timestamp(now),
+		CloseTimeType:    "DEFAULT",
+		CloseTime:        "18:00:00",
+		LocalTime:        now.Format("15:04:05"),
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", request.DispatchDate, now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("parse dispatch date: %s", err)
+	}
+	today := now.Format("2006-01-02")
+	for days := 0; days <= request.NumberOfBusinessDays; date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			continue
+		}
+		days++
+
+		pickupDate := date.Format("2006-01-02")
+		scheduleDay := "FUTURE_DAY"
+		if pickupDate == today {
+			scheduleDay = "SAME_DAY"
+		}
+		for _, carrier := range request.Carriers {
+			reply.Options = append(reply.Options, pickupScheduleOption{
+				Carrier:              carrier,
+				Description:          "Pickup available",
+				ScheduleDay:          scheduleDay,
+				Available:            pickupDate > today || (pickupDate == today && now.Format("15:04:05") < pickupCutOff),
+				PickupDate:           pickupDate,
+				CutOffTime:           pickupCutOff,
+				AccessTime:           pickupAccessTime,
+				ResidentialAvailable: true,
+				CountryRelationship:  "DOMESTIC",
+			})
+		}
+	}
+	return reply, nil
+}
+
+// validateAddresses standardizes addresses with street lines and a postal
+// code by uppercasing them, and classifies them as set by
+// SetAddressClassification
+func (s *Server) validateAddresses(body []byte) (reply, error) {
+	request := addressValidationRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal address validation request: %s", err)
+	}
+
+	reply := &addressValidationReply{
+		replyHeader:    successHeader(namespaceAval, "AddressValidationReply", "aval", 4),
+		ReplyTimestamp: timestamp(s.now()),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, toValidate := range request.AddressesToValidate {
+		input := toValidate.Address
+		result := addressValidationResult{
+			ClientReferenceID: toValidate.ClientReferenceID,
+			State:             "RAW",
+			Classification:    "UNKNOWN",
+			EffectiveAddress:  input,
+		}
+
+		resolved := len(input.StreetLines) > 0 && input.PostalCode != ""
+		if resolved {
+			result.State = "STANDARDIZED"
+			effective := &result.EffectiveAddress
+			effective.StreetLines = nil
+			for _, streetLine := range input.StreetLines {
+				if streetLine != "" {
+					effective.StreetLines = append(effective.StreetLines, strings.ToUpper(streetLine))
+				}
+			}
+			effective.City = strings.ToUpper(input.City)
+			if classification, ok := s.classifications[strings.ToUpper(input.StreetLines[0])]; ok {
+				result.Classification = classification
+			}
+		}
+		result.Attributes = []addressAttribute{
+			{Name: "Resolved", Value: strconv.FormatBool(resolved)},
+			{Name: "CountrySupported", Value: "true"},
+		}
+		reply.AddressResults = append(reply.AddressResults, result)
+	}
+	return reply, nil
+}
+
+func (s *Server) uploadDocument(body []byte) (reply, error) {
+	request := uploadImagesRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal upload images request: %s", err)
+	}
+
+	reply := &uploadImagesReply{
+		replyHeader: successHeader(namespaceUpload, "UploadImagesReply", "cdus", 11),
+	}
+	for _, image := range request.Images {
+		reply.ImageStatuses = append(reply.ImageStatuses, imageStatus{ID: image.ID, Status: "SUCCESS"})
+	}
+	return reply, nil
+}
+
+// nearbyLocation is a canned location, Miles from any searched address
+type nearbyLocation struct {
+	Miles  float64
+	Detail locationDetail
+}
+
+// nearbyLocations are returned by every location search, nearest first
+var nearbyLocations = []nearbyLocation{
+	{Miles: 0.4, Detail: newLocationDetail("SMOK", "FEDEX_OFFICE", "FedEx Office Print & Ship Center",
+		"1440 4th St", "+34.0155-118.4948/",
+		[]string{"ACCEPTS_CASH", "DROP_BOX", "WEEKEND_SERVICES"},
+		[]locationCapability{
+			{CarrierCode: "FDXE", ServiceType: "PRIORITY_OVERNIGHT", TransferOfPossessionType: "DROPOFF"},
+			{CarrierCode: "FDXG", ServiceType: "FEDEX_GROUND", TransferOfPossessionType: "DROPOFF"},
+			{CarrierCode: "FDXE", ServiceType: "PRIORITY_OVERNIGHT", TransferOfPossessionType: "HOLD_AT_LOCATION"},
+		})},
+	{Miles: 0.9, Detail: newLocationDetail("WGNA", "FEDEX_ONSITE", "Walgreens",
+		"1501 Wilshire Blvd", "+34.0262-118.4895/",
+		[]string{"DROP_BOX"},
+		[]locationCapability{
+			{CarrierCode: "FDXG", ServiceType: "FEDEX_GROUND", TransferOfPossessionType: "DROPOFF"},
+			{CarrierCode: "FXSP", ServiceType: "SMART_POST", TransferOfPossessionType: "DROPOFF"},
+		})},
+	{Miles: 3.2, Detail: newLocationDetail("MDRA", "FEDEX_AUTHORIZED_SHIP_CENTER", "Marina Pack & Ship",
+		"4712 Admiralty Way", "+33.9803-118.4517/",
+		[]string{"PACKAGING_SUPPLIES"},
+		[]locationCapability{
+			{CarrierCode: "FDXE", ServiceType: "STANDARD_OVERNIGHT", TransferOfPossessionType: "DROPOFF"},
+			{CarrierCode: "FDXG", ServiceType: "FEDEX_GROUND", TransferOfPossessionType: "DROPOFF"},
+		})},
+}
+
+func newLocationDetail(id, locationType, companyName, streetLine, coordinates string, attributes []string, capabilities []locationCapability) locationDetail {
+	detail := locationDetail{
+		LocationID:            id,
+		GeographicCoordinates: coordinates,
+		LocationType:          locationType,
+		Attributes:            attributes,
+		LocationCapabilities:  capabilities,
+	}
+	detail.LocationContactAndAddress.Contact.CompanyName = companyName
+	detail.LocationContactAndAddress.Contact.PhoneNumber = "3105550100"
+	detail.LocationContactAndAddress.Address = address{
+		StreetLines:         []string{streetLine},
+		City:                "SANTA MONICA",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	}
+
+	for _, day := range []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"} {
+		hours := locationHours{DayofWeek: day, OperationalHoursType: "OPEN_BY_HOURS"}
+		switch day {
+		case "SUN":
+			hours.OperationalHoursType = "CLOSED_ALL_DAY"
+		case "SAT":
+			hours.Hours = []locationTimeRange{{Begins: "10:00:00", Ends: "16:00:00"}}
+		default:
+			hours.Hours = []locationTimeRange{{Begins: "08:00:00", Ends: "20:00:00"}}
+		}
+		detail.NormalHours = append(detail.NormalHours, hours)
+	}
+	return detail
+}
+
+func (s *Server) searchLocations(body []byte) (reply, error) {
+	request := searchLocationsRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal search locations request: %s", err)
+	}
+
+	// radius is in miles
+	radius := request.RadiusDistance.Value
+	if request.RadiusDistance.Units == "KM" {
+		radius /= 1.609344
+	}
+
+	relationship := addressToLocationRelationship{MatchedAddress: request.Address}
+	for _, location := range nearbyLocations {
+		if location.Miles > radius || !includesLocationType(request.LocationTypesToInclude, location.Detail.LocationType) {
+			continue
+		}
+		value := location.Miles
+		if request.RadiusDistance.Units == "KM" {
+			value = math.Round(location.Miles*1.609344*10) / 10
+		}
+		relationship.DistanceAndLocationDetails = append(relationship.DistanceAndLocationDetails, distanceAndLocationDetail{
+			Distance:       distance{Value: value, Units: request.RadiusDistance.Units},
+			LocationDetail: location.Detail,
+		})
+	}
+
+	reply := &searchLocationsReply{
+		replyHeader:           successHeader(namespaceLocs, "SearchLocationsReply", "locs", 12),
+		TotalResultsAvailable: len(relationship.DistanceAndLocationDetails),
+	}
+	if request.ResultsRequested > 0 && len(relationship.DistanceAndLocationDetails) > request.ResultsRequested {
+		relationship.DistanceAndLocationDetails = relationship.DistanceAndLocationDetails[:request.ResultsRequested]
+	}
+	reply.ResultsReturned = len(relationship.DistanceAndLocationDetails)
+	reply.AddressToLocationRelationships = []addressToLocationRelationship{relationship}
+	return reply, nil
+}
+
+// includesLocationType reports whether locationType is in locationTypes, which
+// includes them all when empty
+func includesLocationType(locationTypes []string, locationType string) bool {
+	if len(locationTypes) == 0 {
+		return true
+	}
+	for _, t := range locationTypes {
+		if t == locationType {
+			return true
+		}
+	}
+	return false
+}
+
+// serviceAvailability offers the services in rateServices between US
+// addresses, and international economy between countries. Ground is only
+// offered for YOUR_PACKAGING.
+func (s *Server) serviceAvailability(body []byte) (reply, error) {
+	request := serviceAvailabilityRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal service availability request: %s", err)
+	}
+
+	shipDate, err := time.Parse("2006-01-02", request.ShipDate)
+	if err != nil {
+		return nil, fmt.Errorf("parse ship date %s: %s", request.ShipDate, err)
+	}
+
+	reply := &serviceAvailabilityReply{
+		replyHeader: successHeader(namespaceVacs, "ServiceAvailabilityReply", "vacs", 8),
+	}
+	if request.Destination.PostalCode == "" {
+		return failedReply(reply, Failure{Code: "2", Message: "Destination postal code is missing or invalid."}), nil
+	}
+
+	if request.Origin.CountryCode != request.Destination.CountryCode {
+		delivery := businessDaysAfter(shipDate, 5)
+		reply.Options = append(reply.Options, serviceAvailabilityOption{
+			Service:      "INTERNATIONAL_ECONOMY",
+			DeliveryDate: delivery.Format("2006-01-02"),
+			DeliveryDay:  strings.ToUpper(delivery.Weekday().String()[:3]),
+		})
+		return reply, nil
+	}
+
+	for _, service := range rateServices {
+		if service.ServiceType == "FEDEX_GROUND" && request.Packaging != "" && request.Packaging != "YOUR_PACKAGING" {
+			continue
+		}
+
+		option := serviceAvailabilityOption{Service: service.ServiceType}
+		if service.Commit {
+			delivery := businessDaysAfter(shipDate, service.TransitDays)
+			option.DeliveryDate = delivery.Format("2006-01-02")
+			option.DeliveryDay = strings.ToUpper(delivery.Weekday().String()[:3])
+		} else {
+			option.TransitTime = transitTimes[service.TransitDays-1]
+		}
+		reply.Options = append(reply.Options, option)
+	}
+	return reply, nil
+}
+
+// postalDetails are the postal codes the fake knows, without spaces. American
+// Samoa isn't served by FedEx.
+var postalDetails = map[string]postalDetail{
+	"90401": {StateOrProvinceCode: "CA", CityFirstInitials: "S", CountryCode: "US",
+		LocationDescriptions: []postalLocationDescription{{LocationID: "SMOA", LocationNumber: 904, ServiceArea: "A1", AirportID: "LAX"}}},
+	"10001": {StateOrProvinceCode: "NY", CityFirstInitials: "N", CountryCode: "US",
+		LocationDescriptions: []postalLocationDescription{{LocationID: "NYCA", LocationNumber: 100, ServiceArea: "AM", AirportID: "EWR"}}},
+	"96799": {StateOrProvinceCode: "AS", CityFirstInitials: "P", CountryCode: "US"},
+	"M5V3L9": {StateOrProvinceCode: "ON", CityFirstInitials: "T", CountryCode: "CA",
+		LocationDescriptions: []postalLocationDescription{{LocationID: "YYZA", LocationNumber: 450, ServiceArea: "A2", AirportID: "YYZ"}}},
+}
+
+// validatePostal validates the postal codes in postalDetails, and fails for
+// the others, or when the state doesn't match with CheckForMismatch
+func (s *Server) validatePostal(body []byte) (reply, error) {
+	request := validatePostalRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal validate postal request: %s", err)
+	}
+
+	reply := &validatePostalReply{
+		replyHeader: successHeader(namespaceCnty, "ValidatePostalReply", "cnty", 8),
+	}
+
+	postalCode := strings.ToUpper(strings.ReplaceAll(request.Address.PostalCode, " ", ""))
+	if len(postalCode) > 5 && postalCode[5] == '-' {
+		postalCode = postalCode[:5]
+	}
+	detail, ok := postalDetails[postalCode]
+	if !ok {
+		return failedReply(reply, FailureInvalidAddress), nil
+	}
+	state := strings.ToUpper(request.Address.StateOrProvinceCode)
+	if request.CheckForMismatch && state != "" && state != detail.StateOrProvinceCode {
+		return failedReply(reply, FailureInvalidAddress), nil
+	}
+
+	detail.CleanedPostalCode = postalCode
+	reply.PostalDetail = detail
+	return reply, nil
+}
+
+func (s *Server) closeService(body []byte) (reply, error) {
+	name, err := requestName(body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "GroundCloseRequest":
+		return s.groundClose(body)
+	case "GroundCloseWithDocumentsRequest":
+		return s.groundCloseWithDocuments(body)
+	case "SmartPostCloseRequest":
+		return s.smartPostClose(body)
+	default:
+		return nil, fmt.Errorf("unsupported close service request %s", name)
+	}
+}
+
+var failureNothingToClose = Failure{Code: "9804", Message: "No Shipments to close."}
+
+// groundClose closes the ground packages shipped up to the close time, with a
+// text manifest of their tracking numbers
+func (s *Server) groundClose(body []byte) (reply, error) {
+	request := groundCloseRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal ground close request: %s", err)
+	}
+	closeTime, err := time.Parse(time.RFC3339, request.TimeUpToWhichShipmentsAreToBeClosed)
+	if err != nil {
+		return nil, fmt.Errorf("parse close time %s: %s", request.TimeUpToWhichShipmentsAreToBeClosed, err)
+	}
+
+	reply := &groundCloseReply{
+		replyHeader: successHeader(namespaceClose, "GroundCloseReply", "clos", 5),
+	}
+	// close times are sent to the second
+	trackingNumbers := s.closePackages("FDXG", func(tracking Tracking) bool {
+		return !tracking.ShipTime.Truncate(time.Second).After(closeTime)
+	})
+	if len(trackingNumbers) == 0 {
+		return failedReply(reply, failureNothingToClose), nil
+	}
+
+	reply.Manifest.FileName = fmt.Sprintf("manifest_%s.txt", closeTime.Format("20060102"))
+	reply.Manifest.File = closeManifest("MANIFEST", trackingNumbers)
+	return reply, nil
+}
+
+// groundCloseWithDocuments closes the ground packages shipped on the close
+// date, with a text document of their tracking numbers per requested type
+func (s *Server) groundCloseWithDocuments(body []byte) (reply, error) {
+	request := groundCloseWithDocumentsRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal ground close with documents request: %s", err)
+	}
+
+	reply := &groundCloseDocumentsReply{
+		replyHeader: successHeader(namespaceClose, "GroundCloseDocumentsReply", "clos", 5),
+	}
+	trackingNumbers := s.closePackages("FDXG", func(tracking Tracking) bool {
+		return tracking.ShipTime.Format("2006-01-02") == request.CloseDate
+	})
+	if len(trackingNumbers) == 0 {
+		return failedReply(reply, failureNothingToClose), nil
+	}
+
+	for _, documentType := range request.CloseDocumentTypes {
+		reply.CloseDocuments = append(reply.CloseDocuments, closeDocument{
+			Type:                        documentType,
+			ShippingDocumentDisposition: "RETURNED",
+			Resolution:                  200,
+			CopiesToPrint:               1,
+			Parts:                       []documentPart{{DocumentPartSequenceNumber: 1, Image: closeManifest(documentType, trackingNumbers)}},
+		})
+	}
+	return reply, nil
+}
+
+// smartPostClose closes the SmartPost packages
+func (s *Server) smartPostClose(body []byte) (reply, error) {
+	request := smartPostCloseRequest{}
+	if err := xml.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("unmarshal smartpost close request: %s", err)
+	}
+
+	reply := &smartPostCloseReply{
+		replyHeader: successHeader(namespaceClose, "SmartPostCloseReply", "clos", 5),
+	}
+	if request.HubID == "" {
+		return failedReply(reply, Failure{Code: "2424", Message: "Hub ID is required."}), nil
+	}
+	trackingNumbers := s.closePackages("FXSP", func(Tracking) bool { return true })
+	if len(trackingNumbers) == 0 {
+		return failedReply(reply, failureNothingToClose), nil
+	}
+	return reply, nil
+}
+
+// closePackages closes the packages of carrierCode that aren't closed or
+// cancelled and match, returning their sorted tracking numbers
+func (s *Server) closePackages(carrierCode string, match func(Tracking) bool) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trackingNumbers := []string{}
+	for trackingNumber, tracking := range s.tracking {
+		if tracking.CarrierCode != carrierCode || s.closed[trackingNumber] || isCancelled(tracking) || !match(tracking) {
+			continue
+		}
+		s.closed[trackingNumber] = true
+		trackingNumbers = append(trackingNumbers, trackingNumber)
+	}
+	sort.Strings(trackingNumbers)
+	return trackingNumbers
+}
+
+// closeManifest returns a base64 encoded text document of the tracking
+// numbers
+func closeManifest(documentType string, trackingNumbers []string) string {
+	text := fmt.Sprintf("%s\n%s\n", documentType, strings.Join(trackingNumbers, "\n"))
+	return base64.StdEncoding.EncodeToString([]byte(text))
+}
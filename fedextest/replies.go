@@ -0,0 +1,406 @@
+package fedextest
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Namespaces of the FedEx services
+const (
+	namespaceRate   = "http://fedex.com/ws/rate/v24"
+	namespaceShip   = "http://fedex.com/ws/ship/v23"
+	namespaceTrack  = "http://fedex.com/ws/track/v16"
+	namespacePickup = "http://fedex.com/ws/pickup/v17"
+	namespaceUpload = "http://fedex.com/ws/uploaddocument/v11"
+	namespaceAval   = "http://fedex.com/ws/addressvalidation/v4"
+	namespaceLocs   = "http://fedex.com/ws/locs/v12"
+	namespaceVacs   = "http://fedex.com/ws/vacs/v8"
+	namespaceCnty   = "http://fedex.com/ws/cnty/v8"
+	namespaceClose  = "http://fedex.com/ws/close/v5"
+)
+
+type reply interface {
+	header() *replyHeader
+}
+
+// replyHeader has the fields on every reply. name is the reply element, and
+// isn't marshalled with the rest.
+type replyHeader struct {
+	name            xml.Name
+	HighestSeverity string
+	Notifications   []notification
+	Version         version
+}
+
+func (r *replyHeader) header() *replyHeader {
+	return r
+}
+
+type notification struct {
+	Severity         string
+	Source           string
+	Code             string
+	Message          string
+	LocalizedMessage string
+}
+
+type version struct {
+	ServiceID    string `xml:"ServiceId"`
+	Major        int
+	Intermediate int
+	Minor        int
+}
+
+// successHeader returns the header of a successful reply
+func successHeader(namespace, name, serviceID string, major int) replyHeader {
+	return replyHeader{
+		name:            xml.Name{Space: namespace, Local: name},
+		HighestSeverity: "SUCCESS",
+		Notifications: []notification{{
+			Severity:         "SUCCESS",
+			Source:           serviceID,
+			Code:             "0",
+			Message:          "Request was successfully processed.",
+			LocalizedMessage: "Request was successfully processed.",
+		}},
+		Version: version{ServiceID: serviceID, Major: major},
+	}
+}
+
+// failedReply replaces r with a reply of the same name that only has an
+// ERROR notification
+func failedReply(r reply, failure Failure) reply {
+	header := *r.header()
+	header.HighestSeverity = "ERROR"
+	header.Notifications = []notification{{
+		Severity:         "ERROR",
+		Source:           header.Version.ServiceID,
+		Code:             failure.Code,
+		Message:          failure.Message,
+		LocalizedMessage: failure.Message,
+	}}
+	return &header
+}
+
+// timestamp formats t like FedEx does
+func timestamp(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05-07:00")
+}
+
+type charge struct {
+	Currency string
+	Amount   float64
+}
+
+type rateReply struct {
+	replyHeader
+	RateReplyDetails []rateReplyDetail
+}
+
+type rateReplyDetail struct {
+	ServiceType                     string
+	PackagingType                   string
+	DeliveryTimestamp               string `xml:",omitempty"`
+	IneligibleForMoneyBackGuarantee bool
+	TransitTime                     string `xml:",omitempty"`
+	ActualRateType                  string
+	RatedShipmentDetails            []ratedShipmentDetail
+}
+
+type ratedShipmentDetail struct {
+	ShipmentRateDetail rateDetail
+}
+
+type rateDetail struct {
+	RateType                         string
+	TotalBillingWeight               weight
+	TotalBaseCharge                  charge
+	TotalSurcharges                  charge
+	TotalNetCharge                   charge
+	TotalNetChargeWithDutiesAndTaxes charge
+}
+
+type trackingID struct {
+	TrackingIDType string `xml:"TrackingIdType"`
+	TrackingNumber string
+}
+
+type documentPart struct {
+	DocumentPartSequenceNumber int
+	Image                      string
+}
+
+type shippingDocument struct {
+	Type                        string
+	ShippingDocumentDisposition string
+	ImageType                   string
+	Resolution                  int
+	CopiesToPrint               int
+	Parts                       []documentPart
+}
+
+type processShipmentReply struct {
+	replyHeader
+	CompletedShipmentDetail struct {
+		UsDomestic              bool
+		CarrierCode             string
+		MasterTrackingID        trackingID `xml:"MasterTrackingId"`
+		ServiceTypeDescription  string
+		ShipmentDocuments       []shippingDocument
+		CompletedPackageDetails struct {
+			SequenceNumber int
+			TrackingIds    []trackingID
+			Label          shippingDocument
+		}
+	}
+}
+
+type shipmentReply struct {
+	replyHeader
+}
+
+type trackReply struct {
+	replyHeader
+	CompletedTrackDetails []completedTrackDetail
+}
+
+type completedTrackDetail struct {
+	HighestSeverity   string
+	Notifications     []notification
+	DuplicateWaybill  bool
+	MoreData          bool
+	PagingToken       string `xml:",omitempty"`
+	TrackDetailsCount int    `xml:",omitempty"`
+	TrackDetails      []trackDetail
+}
+
+type dateOrTimestamp struct {
+	Type            string
+	DateOrTimestamp string
+}
+
+type trackEvent struct {
+	Timestamp        string
+	EventType        string
+	EventDescription string
+	Address          address
+}
+
+type trackDetail struct {
+	Notification                         notification
+	TrackingNumber                       string
+	TrackingNumberUniqueIdentifier       string        `xml:",omitempty"`
+	StatusDetail                         *statusDetail `xml:",omitempty"`
+	CarrierCode                          string        `xml:",omitempty"`
+	OperatingCompanyOrCarrierDescription string        `xml:",omitempty"`
+	Service                              *service      `xml:",omitempty"`
+	DatesOrTimes                         []dateOrTimestamp
+	DeliverySignatureName                string `xml:",omitempty"`
+	Events                               []trackEvent
+}
+
+type statusDetail struct {
+	CreationTime string
+	Code         string
+	Description  string
+}
+
+type service struct {
+	Type string
+}
+
+type sendNotificationsReply struct {
+	replyHeader
+	DuplicateWaybill  bool
+	MoreDataAvailable bool
+	Packages          []notificationPackage
+}
+
+type notificationPackage struct {
+	TrackingNumber string
+	CarrierCode    string
+}
+
+type getTrackingDocumentsReply struct {
+	replyHeader
+	Documents []trackingDocument
+}
+
+type trackingDocument struct {
+	DocumentType string
+	ImageType    string
+	Images       []string
+}
+
+type createPickupReply struct {
+	replyHeader
+	PickupConfirmationNumber string
+	Location                 string
+}
+
+type cancelPickupReply struct {
+	replyHeader
+}
+
+type getPickupAvailabilityReply struct {
+	replyHeader
+	RequestTimestamp string
+	Options          []pickupScheduleOption
+	CloseTimeType    string
+	CloseTime        string
+	LocalTime        string
+}
+
+type pickupScheduleOption struct {
+	Carrier              string
+	Description          string
+	ScheduleDay          string
+	Available            bool
+	PickupDate           string
+	CutOffTime           string
+	AccessTime           string
+	ResidentialAvailable bool
+	CountryRelationship  string
+}
+
+type addressValidationReply struct {
+	replyHeader
+	ReplyTimestamp string
+	AddressResults []addressValidationResult
+}
+
+type addressValidationResult struct {
+	ClientReferenceID string `xml:"ClientReferenceId"`
+	State             string
+	Classification    string
+	EffectiveAddress  address
+	Attributes        []addressAttribute
+}
+
+type addressAttribute struct {
+	Name  string
+	Value string
+}
+
+type searchLocationsReply struct {
+	replyHeader
+	TotalResultsAvailable          int
+	ResultsReturned                int
+	AddressToLocationRelationships []addressToLocationRelationship
+}
+
+type addressToLocationRelationship struct {
+	MatchedAddress             address
+	DistanceAndLocationDetails []distanceAndLocationDetail
+}
+
+type distanceAndLocationDetail struct {
+	Distance       distance
+	LocationDetail locationDetail
+}
+
+type distance struct {
+	Value float64
+	Units string
+}
+
+type locationDetail struct {
+	LocationID                string `xml:"LocationId"`
+	StoreNumber               string `xml:",omitempty"`
+	LocationContactAndAddress struct {
+		Contact struct {
+			CompanyName string
+			PhoneNumber string
+		}
+		Address address
+	}
+	GeographicCoordinates string
+	LocationType          string
+	Attributes            []string
+	LocationCapabilities  []locationCapability
+	NormalHours           []locationHours
+}
+
+type locationCapability struct {
+	CarrierCode              string
+	ServiceType              string
+	TransferOfPossessionType string
+}
+
+type locationHours struct {
+	DayofWeek            string
+	OperationalHoursType string
+	Hours                []locationTimeRange
+}
+
+type locationTimeRange struct {
+	Begins string
+	Ends   string
+}
+
+type serviceAvailabilityReply struct {
+	replyHeader
+	Options []serviceAvailabilityOption
+}
+
+type serviceAvailabilityOption struct {
+	Service      string
+	DeliveryDate string `xml:",omitempty"`
+	DeliveryDay  string `xml:",omitempty"`
+	TransitTime  string `xml:",omitempty"`
+}
+
+type validatePostalReply struct {
+	replyHeader
+	PostalDetail postalDetail
+}
+
+type postalDetail struct {
+	StateOrProvinceCode  string
+	CityFirstInitials    string
+	CleanedPostalCode    string
+	CountryCode          string
+	LocationDescriptions []postalLocationDescription
+}
+
+type postalLocationDescription struct {
+	LocationID     string `xml:"LocationId"`
+	LocationNumber int
+	ServiceArea    string `xml:",omitempty"`
+	AirportID      string `xml:"AirportId,omitempty"`
+}
+
+type groundCloseReply struct {
+	replyHeader
+	Manifest struct {
+		FileName string
+		File     string
+	}
+}
+
+type groundCloseDocumentsReply struct {
+	replyHeader
+	CloseDocuments []closeDocument
+}
+
+type closeDocument struct {
+	Type                        string
+	ShippingDocumentDisposition string
+	Resolution                  int
+	CopiesToPrint               int
+	Parts                       []documentPart
+}
+
+type smartPostCloseReply struct {
+	replyHeader
+}
+
+type uploadImagesReply struct {
+	replyHeader
+	ImageStatuses []imageStatus
+}
+
+type imageStatus struct {
+	ID     string `xml:"Id"`
+	Status string
+}
@@ -0,0 +1,183 @@
+package fedextest
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// The request types only have the fields the fake reads. Their tags have no
+// namespace, so they match whatever prefix the client used.
+
+type address struct {
+	StreetLines         []string
+	City                string
+	StateOrProvinceCode string
+	PostalCode          string
+	CountryCode         string
+}
+
+type weight struct {
+	Units string
+	Value float64
+}
+
+type requestedShipment struct {
+	ServiceType string
+	Shipper     struct {
+		Address address
+	}
+	Recipient struct {
+		Address address
+	}
+	SmartPostDetail *struct {
+		HubID string `xml:"HubId"`
+	}
+	LabelSpecification struct {
+		ImageType string
+	}
+	ShippingDocumentSpecification struct {
+		ShippingDocumentTypes []string
+	}
+	MasterTrackingID *struct {
+		TrackingNumber string
+	} `xml:"MasterTrackingId"`
+	PackageCount              int
+	RequestedPackageLineItems []struct {
+		SequenceNumber     int
+		Weight             weight
+		CustomerReferences []struct {
+			CustomerReferenceType string
+			Value                 string
+		}
+	}
+}
+
+type rateRequest struct {
+	ReturnTransitAndCommit bool              `xml:"Body>RateRequest>ReturnTransitAndCommit"`
+	RequestedShipment      requestedShipment `xml:"Body>RateRequest>RequestedShipment"`
+}
+
+type processShipmentRequest struct {
+	RequestedShipment requestedShipment `xml:"Body>ProcessShipmentRequest>RequestedShipment"`
+}
+
+type deleteShipmentRequest struct {
+	TrackingID struct {
+		TrackingNumber string
+	} `xml:"Body>DeleteShipmentRequest>TrackingId"`
+	DeletionControl string `xml:"Body>DeleteShipmentRequest>DeletionControl"`
+}
+
+type selectionDetails struct {
+	CarrierCode       string
+	PackageIdentifier struct {
+		Type  string
+		Value string
+	}
+	ShipDateRangeBegin string
+	ShipDateRangeEnd   string
+	Destination        *struct {
+		PostalCode  string
+		CountryCode string
+	}
+	TrackingNumberUniqueIdentifier string
+	PagingDetail                   *struct {
+		PagingToken string
+	}
+}
+
+type trackRequest struct {
+	SelectionDetails []selectionDetails `xml:"Body>TrackRequest>SelectionDetails"`
+}
+
+type sendNotificationsRequest struct {
+	TrackingNumber string `xml:"Body>SendNotificationsRequest>TrackingNumber"`
+}
+
+type getTrackingDocumentsRequest struct {
+	SelectionDetails []selectionDetails `xml:"Body>GetTrackingDocumentsRequest>SelectionDetails"`
+	ImageType        string             `xml:"Body>GetTrackingDocumentsRequest>TrackingDocumentSpecification>SignatureProofOfDeliveryLetterDetail>DocumentFormat>ImageType"`
+}
+
+type createPickupRequest struct {
+	OriginDetail struct {
+		PickupLocation struct {
+			Address address
+		}
+		ReadyTimestamp string
+	} `xml:"Body>CreatePickupRequest>OriginDetail"`
+}
+
+type cancelPickupRequest struct {
+	PickupConfirmationNumber string `xml:"Body>CancelPickupRequest>PickupConfirmationNumber"`
+	ScheduledDate            string `xml:"Body>CancelPickupRequest>ScheduledDate"`
+}
+
+type getPickupAvailabilityRequest struct {
+	DispatchDate         string   `xml:"Body>GetPickupAvailabilityRequest>DispatchDate"`
+	NumberOfBusinessDays int      `xml:"Body>GetPickupAvailabilityRequest>NumberOfBusinessDays"`
+	Carriers             []string `xml:"Body>GetPickupAvailabilityRequest>Carriers"`
+}
+
+type addressValidationRequest struct {
+	AddressesToValidate []struct {
+		ClientReferenceID string `xml:"ClientReferenceId"`
+		Address           address
+	} `xml:"Body>AddressValidationRequest>AddressesToValidate"`
+}
+
+type searchLocationsRequest struct {
+	Address        address `xml:"Body>SearchLocationsRequest>Address"`
+	RadiusDistance struct {
+		Value float64
+		Units string
+	} `xml:"Body>SearchLocationsRequest>Constraints>RadiusDistance"`
+	ResultsRequested       int      `xml:"Body>SearchLocationsRequest>Constraints>ResultsRequested"`
+	LocationTypesToInclude []string `xml:"Body>SearchLocationsRequest>Constraints>LocationTypesToInclude"`
+}
+
+type serviceAvailabilityRequest struct {
+	Origin      address `xml:"Body>ServiceAvailabilityRequest>Origin"`
+	Destination address `xml:"Body>ServiceAvailabilityRequest>Destination"`
+	ShipDate    string  `xml:"Body>ServiceAvailabilityRequest>ShipDate"`
+	Packaging   string  `xml:"Body>ServiceAvailabilityRequest>Packaging"`
+}
+
+type validatePostalRequest struct {
+	Address          address `xml:"Body>ValidatePostalRequest>Address"`
+	CheckForMismatch bool    `xml:"Body>ValidatePostalRequest>CheckForMismatch"`
+}
+
+type groundCloseRequest struct {
+	TimeUpToWhichShipmentsAreToBeClosed string `xml:"Body>GroundCloseRequest>TimeUpToWhichShipmentsAreToBeClosed"`
+}
+
+type groundCloseWithDocumentsRequest struct {
+	CloseDate          string   `xml:"Body>GroundCloseWithDocumentsRequest>CloseDate"`
+	CloseDocumentTypes []string `xml:"Body>GroundCloseWithDocumentsRequest>CloseDocumentSpecification>CloseDocumentTypes"`
+}
+
+type smartPostCloseRequest struct {
+	HubID string `xml:"Body>SmartPostCloseRequest>HubId"`
+}
+
+type uploadImagesRequest struct {
+	Images []struct {
+		ID string `xml:"Id"`
+	} `xml:"Body>UploadImagesRequest>Images"`
+}
+
+// requestName returns the name of the request element in the SOAP body
+func requestName(body []byte) (string, error) {
+	var envelope struct {
+		Body struct {
+			Request struct {
+				XMLName xml.Name
+			} `xml:",any"`
+		}
+	}
+	if err := xml.Unmarshal(body, &envelope); err != nil {
+		return "", fmt.Errorf("unmarshal envelope: %s", err)
+	}
+	return envelope.Body.Request.XMLName.Local, nil
+}
@@ -0,0 +1,288 @@
+// Package fedextest provides an in-process fake of the FedEx SOAP API, so code
+// using this library can be tested without FedEx credentials or network
+// access. Point API.FedExURL at Server.URL.
+package fedextest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints served by Server
+const (
+	EndpointRate              = "/rate/v24"
+	EndpointShip              = "/ship/v23"
+	EndpointTrack             = "/trck"
+	EndpointSendNotifications = "/track/v16"
+	EndpointPickup            = "/pickup/v17"
+	EndpointUploadDocument    = "/uploaddocument/v11"
+	EndpointAddressValidation = "/addressvalidation/v4"
+	EndpointLocations         = "/locs/v12"
+	EndpointAvailability      = "/vacs/v8"
+	EndpointCountry           = "/cnty/v8"
+	EndpointClose             = "/close/v5"
+)
+
+// Server is a fake FedEx API. Replies are canned, but shipments it creates
+// can be tracked and deleted until they're picked up, and closed once, and
+// pickups can only be created once per location and day until they're
+// cancelled.
+type Server struct {
+	*httptest.Server
+
+	mu                 sync.Mutex
+	failures           map[string][]Failure
+	tracking           map[string]Tracking
+	duplicates         map[string][]Tracking
+	trackPageSize      int
+	pickups            map[string]string
+	classifications    map[string]string
+	closed             map[string]bool
+	numPickups         int
+	requests           []Request
+	nextTrackingNumber int
+	now                func() time.Time
+}
+
+// Request is a request received by Server
+type Request struct {
+	Path string
+	Body string
+}
+
+// Failure scripts a failed reply. With a StatusCode, the reply is that HTTP
+// status with Body. Otherwise, it is a FedEx reply with an ERROR notification.
+type Failure struct {
+	StatusCode int
+	Body       string
+
+	Code    string
+	Message string
+}
+
+// Common failures
+var (
+	FailureServiceUnavailable = Failure{StatusCode: http.StatusServiceUnavailable, Body: "<html><body>Service Unavailable</body></html>"}
+	FailureAuthentication     = Failure{Code: "1000", Message: "Authentication Failed"}
+	FailureInvalidAddress     = Failure{Code: "8522", Message: "Invalid postal code for the state."}
+)
+
+// Tracking is the tracking history of a package
+type Tracking struct {
+	CarrierCode       string
+	ServiceType       string
+	ShipTime          time.Time
+	EstimatedDelivery time.Time
+	ActualDelivery    time.Time
+	// MasterTrackingNumber is the tracking number of the master package of
+	// a multi-piece shipment, empty for the master package itself
+	MasterTrackingNumber string
+	// DeliverySignatureName is who signed for the package, once delivered
+	DeliverySignatureName string
+	// References the package can be tracked by, besides its tracking number
+	References             []Reference
+	DestinationPostalCode  string
+	DestinationCountryCode string
+	// Events are the scans of the package, most recent first, as FedEx
+	// returns them. The current status is the one of the first event.
+	Events []TrackingEvent
+}
+
+// Reference is a reference a package was shipped with. Type is a package
+// identifier type, e.g. RMA.
+type Reference struct {
+	Type  string
+	Value string
+}
+
+// TrackingEvent is a scan of a package
+type TrackingEvent struct {
+	Timestamp           time.Time
+	EventType           string
+	EventDescription    string
+	City                string
+	StateOrProvinceCode string
+	PostalCode          string
+	CountryCode         string
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		failures:           map[string][]Failure{},
+		tracking:           map[string]Tracking{},
+		duplicates:         map[string][]Tracking{},
+		pickups:            map[string]string{},
+		classifications:    map[string]string{},
+		closed:             map[string]bool{},
+		nextTrackingNumber: 1,
+		now:                time.Now,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(EndpointRate, s.handle(s.rate))
+	mux.HandleFunc(EndpointShip, s.handle(s.shipService))
+	mux.HandleFunc(EndpointTrack, s.handle(s.track))
+	mux.HandleFunc(EndpointSendNotifications, s.handle(s.trackService))
+	mux.HandleFunc(EndpointPickup, s.handle(s.pickupService))
+	mux.HandleFunc(EndpointUploadDocument, s.handle(s.uploadDocument))
+	mux.HandleFunc(EndpointAddressValidation, s.handle(s.validateAddresses))
+	mux.HandleFunc(EndpointLocations, s.handle(s.searchLocations))
+	mux.HandleFunc(EndpointAvailability, s.handle(s.serviceAvailability))
+	mux.HandleFunc(EndpointCountry, s.handle(s.validatePostal))
+	mux.HandleFunc(EndpointClose, s.handle(s.closeService))
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// FailNext makes the next requests to endpoint fail, in order
+func (s *Server) FailNext(endpoint string, failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = append(s.failures[endpoint], failures...)
+}
+
+// FailAfter lets the next n requests to endpoint succeed, and makes the
+// following ones fail, in order
+func (s *Server) FailAfter(endpoint string, n int, failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[endpoint] = append(s.failures[endpoint], make([]Failure, n)...)
+	s.failures[endpoint] = append(s.failures[endpoint], failures...)
+}
+
+// SetTracking sets the tracking history of trackingNumber. Unknown tracking
+// numbers aren't found.
+func (s *Server) SetTracking(trackingNumber string, tracking Tracking) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracking[trackingNumber] = tracking
+}
+
+// SetDuplicateTracking makes trackingNumber a duplicate waybill, reused for
+// several shipments. Track replies have every candidate, unless one is
+// selected by its unique identifier, which has its ship date.
+func (s *Server) SetDuplicateTracking(trackingNumber string, candidates ...Tracking) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.duplicates[trackingNumber] = candidates
+}
+
+// SetTrackPageSize pages track replies with more than n track details. Zero,
+// the default, doesn't page them.
+func (s *Server) SetTrackPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trackPageSize = n
+}
+
+// SetAddressClassification classifies the addresses of streetLine as
+// BUSINESS, RESIDENTIAL or MIXED. Other addresses are UNKNOWN.
+func (s *Server) SetAddressClassification(streetLine, classification string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.classifications[strings.ToUpper(streetLine)] = classification
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// handler builds the reply to a request body
+type handler func(body []byte) (reply, error)
+
+func (s *Server) handle(h handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Path: r.URL.Path, Body: string(body)})
+		failure, hasFailure := s.popFailure(r.URL.Path)
+		s.mu.Unlock()
+
+		if hasFailure && failure.StatusCode != 0 {
+			w.WriteHeader(failure.StatusCode)
+			w.Write([]byte(failure.Body))
+			return
+		}
+
+		reply, err := h(body)
+		if err != nil {
+			writeFault(w, err)
+			return
+		}
+		if hasFailure {
+			reply = failedReply(reply, failure)
+		}
+		writeReply(w, reply)
+	}
+}
+
+func (s *Server) popFailure(endpoint string) (Failure, bool) {
+	failures := s.failures[endpoint]
+	if len(failures) == 0 {
+		return Failure{}, false
+	}
+	s.failures[endpoint] = failures[1:]
+	// FailAfter queues zero failures for the requests that succeed
+	return failures[0], failures[0] != Failure{}
+}
+
+const (
+	envelopeStart = `<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/"><SOAP-ENV:Header/><SOAP-ENV:Body>`
+	envelopeEnd   = `</SOAP-ENV:Body></SOAP-ENV:Envelope>`
+)
+
+type soapFault struct {
+	XMLName     xml.Name `xml:"SOAP-ENV:Fault"`
+	FaultCode   string   `xml:"faultcode"`
+	FaultString string   `xml:"faultstring"`
+	Detail      struct {
+		Desc string `xml:"desc"`
+	} `xml:"detail"`
+}
+
+// writeReply writes reply in a SOAP envelope, named and namespaced like FedEx
+// does
+func writeReply(w http.ResponseWriter, r reply) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	buf.WriteString(envelopeStart)
+	if err := xml.NewEncoder(buf).EncodeElement(r, xml.StartElement{Name: r.header().name}); err != nil {
+		writeFault(w, fmt.Errorf("marshal reply: %s", err))
+		return
+	}
+	buf.WriteString(envelopeEnd)
+
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	w.Write(buf.Bytes())
+}
+
+// writeFault replies with a SOAP Fault, like FedEx does for requests it can't
+// read
+func writeFault(w http.ResponseWriter, err error) {
+	fault := soapFault{FaultCode: "SOAP-ENV:Client", FaultString: "Fault"}
+	fault.Detail.Desc = err.Error()
+	content, _ := xml.Marshal(fault)
+
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(envelopeStart))
+	w.Write(content)
+	w.Write([]byte(envelopeEnd))
+}
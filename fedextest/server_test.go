@@ -0,0 +1,97 @@
+package fedextest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex"
+	"github.com/happyreturns/fedex/api"
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+var fromAndTo = models.FromAndTo{
+	FromAddress: models.Address{
+		StreetLines:         []string{"1517 Lincoln Blvd"},
+		City:                "Santa Monica",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	},
+	ToAddress: models.Address{
+		StreetLines:         []string{"1106 Broadway"},
+		City:                "Santa Monica",
+		StateOrProvinceCode: "CA",
+		PostalCode:          "90401",
+		CountryCode:         "US",
+	},
+	FromContact: models.Contact{PersonName: "Joe Customer", PhoneNumber: "2135550000"},
+	ToContact:   models.Contact{CompanyName: "Happy Returns", PhoneNumber: "4243259510"},
+}
+
+func newServer() (*fedextest.Server, fedex.Fedex) {
+	server := fedextest.NewServer()
+	return server, newFedex(server)
+}
+
+func newFedex(server *fedextest.Server) fedex.Fedex {
+	return fedex.Fedex{API: api.API{
+		Key:         "key",
+		Password:    "password",
+		Account:     "account",
+		Meter:       "meter",
+		FedExURL:    server.URL,
+		Logger:      api.NopLogger{},
+		RetryPolicy: &api.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}}
+}
+
+func TestShipAndTrack(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+
+	reply, err := f.Ship(&models.Shipment{FromAndTo: fromAndTo, Service: "fedex_ground"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	label, imageType, err := reply.LabelDataAndImageType()
+	if err != nil || len(label) == 0 || imageType != "PNG" {
+		t.Fatal("should have a png label", err)
+	}
+
+	trackingNumber := reply.CompletedShipmentDetail.CompletedPackageDetails.TrackingIds[0].TrackingNumber
+	trackReply, err := f.TrackByNumber(fedex.CarrierCodeGround, trackingNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := trackReply.CompletedTrackDetails[0].TrackDetails[0].StatusDetail.Code; status != "OC" {
+		t.Fatal("new shipment should be label created, got", status)
+	}
+
+	_, err = f.TrackByNumber(fedex.CarrierCodeGround, "999999999999")
+	if !errors.Is(err, models.ErrTrackingNotFound) {
+		t.Fatal("unknown tracking number should not be found", err)
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	server, f := newServer()
+	defer server.Close()
+
+	// Unavailable is retried
+	server.FailNext(fedextest.EndpointRate, fedextest.FailureServiceUnavailable)
+	if _, err := f.Rate(&models.Rate{FromAndTo: fromAndTo}); err != nil {
+		t.Fatal(err)
+	}
+
+	server.FailNext(fedextest.EndpointShip, fedextest.FailureAuthentication)
+	_, err := f.Ship(&models.Shipment{FromAndTo: fromAndTo})
+	if !errors.Is(err, models.ErrAuthFailure) {
+		t.Fatal("should fail authentication", err)
+	}
+
+	if numRequests := len(server.Requests()); numRequests != 3 {
+		t.Fatal("should have received 3 requests, got", numRequests)
+	}
+}
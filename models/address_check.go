@@ -0,0 +1,303 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// countryCodes are the ISO 3166-1 alpha-2 country codes
+var countryCodes = map[string]bool{}
+
+func init() {
+	codes := `
+		AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ
+		BL BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR
+		CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR
+		GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU
+		ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ
+		LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ
+		MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF
+		PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI
+		SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR
+		TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`
+	for _, code := range strings.Fields(codes) {
+		countryCodes[code] = true
+	}
+}
+
+// usStates are the US states, territories and military state codes
+var usStates = map[string]string{
+	"AA": "Armed Forces Americas",
+	"AE": "Armed Forces Europe",
+	"AK": "Alaska",
+	"AL": "Alabama",
+	"AP": "Armed Forces Pacific",
+	"AR": "Arkansas",
+	"AS": "American Samoa",
+	"AZ": "Arizona",
+	"CA": "California",
+	"CO": "Colorado",
+	"CT": "Connecticut",
+	"DC": "District of Columbia",
+	"DE": "Delaware",
+	"FL": "Florida",
+	"FM": "Federated States of Micronesia",
+	"GA": "Georgia",
+	"GU": "Guam",
+	"HI": "Hawaii",
+	"IA": "Iowa",
+	"ID": "Idaho",
+	"IL": "Illinois",
+	"IN": "Indiana",
+	"KS": "Kansas",
+	"KY": "Kentucky",
+	"LA": "Louisiana",
+	"MA": "Massachusetts",
+	"MD": "Maryland",
+	"ME": "Maine",
+	"MH": "Marshall Islands",
+	"MI": "Michigan",
+	"MN": "Minnesota",
+	"MO": "Missouri",
+	"MP": "Northern Mariana Islands",
+	"MS": "Mississippi",
+	"MT": "Montana",
+	"NC": "North Carolina",
+	"ND": "North Dakota",
+	"NE": "Nebraska",
+	"NH": "New Hampshire",
+	"NJ": "New Jersey",
+	"NM": "New Mexico",
+	"NV": "Nevada",
+	"NY": "New York",
+	"OH": "Ohio",
+	"OK": "Oklahoma",
+	"OR": "Oregon",
+	"PA": "Pennsylvania",
+	"PR": "Puerto Rico",
+	"PW": "Palau",
+	"RI": "Rhode Island",
+	"SC": "South Carolina",
+	"SD": "South Dakota",
+	"TN": "Tennessee",
+	"TX": "Texas",
+	"UT": "Utah",
+	"VA": "Virginia",
+	"VI": "Virgin Islands",
+	"VT": "Vermont",
+	"WA": "Washington",
+	"WI": "Wisconsin",
+	"WV": "West Virginia",
+	"WY": "Wyoming",
+}
+
+// caProvinces are the Canadian provinces and territories
+var caProvinces = map[string]string{
+	"AB": "Alberta",
+	"BC": "British Columbia",
+	"MB": "Manitoba",
+	"NB": "New Brunswick",
+	"NL": "Newfoundland and Labrador",
+	"NS": "Nova Scotia",
+	"NT": "Northwest Territories",
+	"NU": "Nunavut",
+	"ON": "Ontario",
+	"PE": "Prince Edward Island",
+	"QC": "Quebec",
+	"SK": "Saskatchewan",
+	"YT": "Yukon",
+}
+
+// usZIP3States are the states of ranges of the first three digits of ZIP
+// codes. A few ranges are shared by several states.
+var usZIP3States = []struct {
+	From, To int
+	States   []string
+}{
+	{5, 5, []string{"NY"}},
+	{6, 7, []string{"PR"}},
+	{8, 8, []string{"VI"}},
+	{9, 9, []string{"PR"}},
+	{10, 27, []string{"MA"}},
+	{28, 29, []string{"RI"}},
+	{30, 38, []string{"NH"}},
+	{39, 49, []string{"ME"}},
+	{50, 54, []string{"VT"}},
+	{55, 55, []string{"MA"}},
+	{56, 59, []string{"VT"}},
+	{60, 69, []string{"CT"}},
+	{70, 89, []string{"NJ"}},
+	{90, 99, []string{"AE"}},
+	{100, 149, []string{"NY"}},
+	{150, 196, []string{"PA"}},
+	{197, 199, []string{"DE"}},
+	{200, 200, []string{"DC"}},
+	{201, 201, []string{"VA"}},
+	{202, 205, []string{"DC"}},
+	{206, 219, []string{"MD"}},
+	{220, 246, []string{"VA"}},
+	{247, 268, []string{"WV"}},
+	{270, 289, []string{"NC"}},
+	{290, 299, []string{"SC"}},
+	{300, 319, []string{"GA"}},
+	{320, 339, []string{"FL"}},
+	{340, 340, []string{"AA"}},
+	{341, 349, []string{"FL"}},
+	{350, 369, []string{"AL"}},
+	{370, 385, []string{"TN"}},
+	{386, 397, []string{"MS"}},
+	{398, 399, []string{"GA"}},
+	{400, 427, []string{"KY"}},
+	{430, 459, []string{"OH"}},
+	{460, 479, []string{"IN"}},
+	{480, 499, []string{"MI"}},
+	{500, 528, []string{"IA"}},
+	{530, 549, []string{"WI"}},
+	{550, 567, []string{"MN"}},
+	{569, 569, []string{"DC"}},
+	{570, 577, []string{"SD"}},
+	{580, 588, []string{"ND"}},
+	{590, 599, []string{"MT"}},
+	{600, 629, []string{"IL"}},
+	{630, 658, []string{"MO"}},
+	{660, 679, []string{"KS"}},
+	{680, 693, []string{"NE"}},
+	{700, 715, []string{"LA"}},
+	{716, 729, []string{"AR"}},
+	{730, 732, []string{"OK"}},
+	{733, 733, []string{"TX"}},
+	{734, 749, []string{"OK"}},
+	{750, 799, []string{"TX"}},
+	{800, 816, []string{"CO"}},
+	{820, 831, []string{"WY"}},
+	{832, 838, []string{"ID"}},
+	{840, 847, []string{"UT"}},
+	{850, 865, []string{"AZ"}},
+	{870, 884, []string{"NM"}},
+	{885, 885, []string{"TX"}},
+	{889, 898, []string{"NV"}},
+	{900, 961, []string{"CA"}},
+	{962, 966, []string{"AP"}},
+	{967, 968, []string{"HI", "AS"}},
+	{969, 969, []string{"GU", "MP", "FM", "MH", "PW"}},
+	{970, 979, []string{"OR"}},
+	{980, 994, []string{"WA"}},
+	{995, 999, []string{"AK"}},
+}
+
+// caPostalCodeProvinces are the provinces of the first letter of postal
+// codes. X is shared by the Northwest Territories and Nunavut.
+var caPostalCodeProvinces = map[byte][]string{
+	'A': {"NL"},
+	'B': {"NS"},
+	'C': {"PE"},
+	'E': {"NB"},
+	'G': {"QC"},
+	'H': {"QC"},
+	'J': {"QC"},
+	'K': {"ON"},
+	'L': {"ON"},
+	'M': {"ON"},
+	'N': {"ON"},
+	'P': {"ON"},
+	'R': {"MB"},
+	'S': {"SK"},
+	'T': {"AB"},
+	'V': {"BC"},
+	'X': {"NT", "NU"},
+	'Y': {"YT"},
+}
+
+var (
+	usZIPCodeRegex    = regexp.MustCompile(`^\d{5}(-?\d{4})?$`)
+	caPostalCodeRegex = regexp.MustCompile(`^[A-Z]\d[A-Z]\d[A-Z]\d$`)
+)
+
+// NormalizedCountryCode returns the upper case country code of a, US when it
+// has none
+func (a Address) NormalizedCountryCode() string {
+	countryCode := strings.ToUpper(strings.TrimSpace(a.CountryCode))
+	if countryCode == "" {
+		return "US"
+	}
+	return countryCode
+}
+
+// Check checks a without calling FedEx: that its country code exists and,
+// for US and Canadian addresses, that its state or province exists and its
+// postal code is well formed and belongs to it. Errors wrap
+// ErrInvalidAddress.
+func (a Address) Check() error {
+	countryCode := a.NormalizedCountryCode()
+	if !countryCodes[countryCode] {
+		return fmt.Errorf("%w: unknown country code %s", ErrInvalidAddress, a.CountryCode)
+	}
+
+	state := strings.ToUpper(strings.TrimSpace(a.StateOrProvinceCode))
+	postalCode := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(a.PostalCode), " ", ""))
+
+	switch countryCode {
+	case "US":
+		if _, ok := usStates[state]; !ok {
+			return fmt.Errorf("%w: unknown US state %s", ErrInvalidAddress, a.StateOrProvinceCode)
+		}
+		if !usZIPCodeRegex.MatchString(postalCode) {
+			return fmt.Errorf("%w: malformed ZIP code %s", ErrInvalidAddress, a.PostalCode)
+		}
+		states := USZIPCodeStates(postalCode)
+		if !containsString(states, state) {
+			return fmt.Errorf("%w: ZIP code %s is in %s, not %s", ErrInvalidAddress, a.PostalCode, strings.Join(states, " or "), state)
+		}
+	case "CA":
+		if _, ok := caProvinces[state]; !ok {
+			return fmt.Errorf("%w: unknown Canadian province %s", ErrInvalidAddress, a.StateOrProvinceCode)
+		}
+		if !caPostalCodeRegex.MatchString(postalCode) {
+			return fmt.Errorf("%w: malformed postal code %s", ErrInvalidAddress, a.PostalCode)
+		}
+		provinces := CAPostalCodeProvinces(postalCode)
+		if !containsString(provinces, state) {
+			return fmt.Errorf("%w: postal code %s is in %s, not %s", ErrInvalidAddress, a.PostalCode, strings.Join(provinces, " or "), state)
+		}
+	}
+	return nil
+}
+
+// USZIPCodeStates returns the states the ZIP code may be in, or nil when it
+// isn't a known ZIP code prefix
+func USZIPCodeStates(zipCode string) []string {
+	if len(zipCode) < 3 {
+		return nil
+	}
+	zip3, err := strconv.Atoi(zipCode[:3])
+	if err != nil {
+		return nil
+	}
+	for _, zip3States := range usZIP3States {
+		if zip3 >= zip3States.From && zip3 <= zip3States.To {
+			return zip3States.States
+		}
+	}
+	return nil
+}
+
+// CAPostalCodeProvinces returns the provinces the postal code may be in, or
+// nil when it doesn't start with a Canadian postal code letter
+func CAPostalCodeProvinces(postalCode string) []string {
+	postalCode = strings.ToUpper(strings.TrimSpace(postalCode))
+	if postalCode == "" {
+		return nil
+	}
+	return caPostalCodeProvinces[postalCode[0]]
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
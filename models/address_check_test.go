@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddressCheck(t *testing.T) {
+	valid := []Address{
+		{StateOrProvinceCode: "CA", PostalCode: "90401", CountryCode: "US"},
+		{StateOrProvinceCode: "ny", PostalCode: "10001-1234"},
+		{StateOrProvinceCode: "ON", PostalCode: "m5v 3l9", CountryCode: "CA"},
+		{StateOrProvinceCode: "NU", PostalCode: "X0A 0H0", CountryCode: "CA"},
+		{PostalCode: "75008", CountryCode: "FR"},
+	}
+	for _, address := range valid {
+		if err := address.Check(); err != nil {
+			t.Fatal("address should be valid", address, err)
+		}
+	}
+
+	invalid := []Address{
+		{StateOrProvinceCode: "NY", PostalCode: "90401"},
+		{StateOrProvinceCode: "XX", PostalCode: "90401"},
+		{StateOrProvinceCode: "CA", PostalCode: "9040"},
+		{StateOrProvinceCode: "QC", PostalCode: "M5V 3L9", CountryCode: "CA"},
+		{PostalCode: "75008", CountryCode: "ZZ"},
+	}
+	for _, address := range invalid {
+		if err := address.Check(); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatal("address should be invalid", address, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import "strings"
+
+type AddressValidationBody struct {
+	AddressValidationRequest AddressValidationRequest `xml:"q0:AddressValidationRequest"`
+}
+
+type AddressValidationRequest struct {
+	Request
+	InEffectAsOfTimestamp Timestamp           `xml:"q0:InEffectAsOfTimestamp"`
+	AddressesToValidate   []AddressToValidate `xml:"q0:AddressesToValidate"`
+}
+
+type AddressToValidate struct {
+	ClientReferenceID string  `xml:"q0:ClientReferenceId"`
+	Address           Address `xml:"q0:Address"`
+}
+
+type AddressValidationResponseEnvelope struct {
+	Reply AddressValidationReply `xml:"Body>AddressValidationReply"`
+}
+
+func (a *AddressValidationResponseEnvelope) Error() error {
+	return a.Reply.replyError("AddressValidation")
+}
+
+func (a *AddressValidationResponseEnvelope) Warnings() []Warning {
+	return a.Reply.Warnings()
+}
+
+// AddressValidationReply : AddressValidation reply root (`xml:"Body>AddressValidationReply"`)
+type AddressValidationReply struct {
+	Reply
+	ReplyTimestamp Timestamp
+	AddressResults []AddressValidationResult
+}
+
+// AddressValidationResult is the validation of one address
+type AddressValidationResult struct {
+	ClientReferenceID string `xml:"ClientReferenceId"`
+	// State is STANDARDIZED when FedEx matched the address to postal data,
+	// NORMALIZED when it only formatted it, or RAW
+	State string
+	// Classification is BUSINESS, RESIDENTIAL, MIXED or UNKNOWN
+	Classification   string
+	EffectiveAddress AddressReply
+	// Attributes say what FedEx found and changed, like Resolved,
+	// SuiteRequiredButMissing or ZIP4Match, with "true" or "false" values
+	Attributes []AddressAttribute
+}
+
+type AddressAttribute struct {
+	Name  string
+	Value string
+}
+
+// Address returns the resolved or standardized address, with Residential set
+// when it's classified as residential
+func (a AddressValidationResult) Address() Address {
+	address := a.EffectiveAddress.Address()
+	address.Residential = Bool(a.Classification == AddressClassificationResidential)
+	return address
+}
+
+// Attribute returns the value of the attribute name, and whether there is one
+func (a AddressValidationResult) Attribute(name string) (string, bool) {
+	for _, attribute := range a.Attributes {
+		if attribute.Name == name {
+			return attribute.Value, true
+		}
+	}
+	return "", false
+}
+
+// Resolved reports whether FedEx matched the address to a known address
+func (a AddressValidationResult) Resolved() bool {
+	value, _ := a.Attribute(AddressAttributeResolved)
+	return value == "true"
+}
+
+// ChangedFields returns the names of the Address fields FedEx changed in
+// original, ignoring case and spacing
+func (a AddressValidationResult) ChangedFields(original Address) []string {
+	effective := a.Address()
+	fields := []struct {
+		name               string
+		original, resolved string
+	}{
+		{"StreetLines", strings.Join(original.StreetLines, " "), strings.Join(effective.StreetLines, " ")},
+		{"City", original.City, effective.City},
+		{"StateOrProvinceCode", original.StateOrProvinceCode, effective.StateOrProvinceCode},
+		{"PostalCode", original.PostalCode, effective.PostalCode},
+		{"CountryCode", original.CountryCode, effective.CountryCode},
+	}
+
+	changed := []string{}
+	for _, field := range fields {
+		if normalizeAddressField(field.original) != normalizeAddressField(field.resolved) {
+			changed = append(changed, field.name)
+		}
+	}
+	return changed
+}
+
+func normalizeAddressField(value string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(value), " "))
+}
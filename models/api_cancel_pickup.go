@@ -0,0 +1,31 @@
+package models
+
+type CancelPickupBody struct {
+	CancelPickupRequest CancelPickupRequest `xml:"q0:CancelPickupRequest"`
+}
+
+type CancelPickupRequest struct {
+	Request
+	CarrierCode              string `xml:"q0:CarrierCode"`
+	PickupConfirmationNumber string `xml:"q0:PickupConfirmationNumber"`
+	ScheduledDate            string `xml:"q0:ScheduledDate"`
+	Location                 string `xml:"q0:Location,omitempty"`
+	Remarks                  string `xml:"q0:Remarks,omitempty"`
+}
+
+type CancelPickupResponseEnvelope struct {
+	Reply CancelPickupReply `xml:"Body>CancelPickupReply"`
+}
+
+func (c *CancelPickupResponseEnvelope) Error() error {
+	return c.Reply.replyError("CancelPickup")
+}
+
+func (c *CancelPickupResponseEnvelope) Warnings() []Warning {
+	return c.Reply.Warnings()
+}
+
+// CancelPickupReply : CancelPickup reply root (`xml:"Body>CancelPickupReply"`)
+type CancelPickupReply struct {
+	Reply
+}
@@ -6,11 +6,23 @@ import "time"
 type Pickup struct {
 	PickupLocation PickupLocation
 	ToAddress      Address
+	// CarrierCode is the carrier picking up, CarrierCodeFDXG when empty
+	CarrierCode string
+}
+
+// Carrier returns the carrier picking up p
+func (p Pickup) Carrier() string {
+	if p.CarrierCode == "" {
+		return CarrierCodeFDXG
+	}
+	return p.CarrierCode
 }
 
 type PickupSuccess struct {
 	ConfirmationNumber string
-	Window             PickupTimeWindow
+	// Location is the FedEx location doing the pickup, needed to cancel it
+	Location string
+	Window   PickupTimeWindow
 }
 
 type PickupTimeWindow struct {
@@ -37,7 +49,11 @@ type CreatePickupResponseEnvelope struct {
 }
 
 func (c *CreatePickupResponseEnvelope) Error() error {
-	return c.Reply.Error()
+	return c.Reply.replyError("CreatePickup")
+}
+
+func (c *CreatePickupResponseEnvelope) Warnings() []Warning {
+	return c.Reply.Warnings()
 }
 
 // CreatePickupReply : CreatePickup reply root (`xml:"Body>CreatePickupReply"`)
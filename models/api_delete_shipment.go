@@ -0,0 +1,28 @@
+package models
+
+type DeleteShipmentBody struct {
+	DeleteShipmentRequest DeleteShipmentRequest `xml:"q0:DeleteShipmentRequest"`
+}
+
+type DeleteShipmentRequest struct {
+	Request
+	TrackingID      RequestedTrackingID `xml:"q0:TrackingId"`
+	DeletionControl string              `xml:"q0:DeletionControl"`
+}
+
+type DeleteShipmentResponseEnvelope struct {
+	Reply ShipmentReply `xml:"Body>ShipmentReply"`
+}
+
+func (d *DeleteShipmentResponseEnvelope) Error() error {
+	return d.Reply.replyError("DeleteShipment")
+}
+
+func (d *DeleteShipmentResponseEnvelope) Warnings() []Warning {
+	return d.Reply.Warnings()
+}
+
+// ShipmentReply : DeleteShipment reply root (`xml:"Body>ShipmentReply"`)
+type ShipmentReply struct {
+	Reply
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+type GetPickupAvailabilityBody struct {
+	GetPickupAvailabilityRequest GetPickupAvailabilityRequest `xml:"q0:GetPickupAvailabilityRequest"`
+}
+
+type GetPickupAvailabilityRequest struct {
+	Request
+	PickupAddress        Address  `xml:"q0:PickupAddress"`
+	PickupRequestType    []string `xml:"q0:PickupRequestType"`
+	DispatchDate         string   `xml:"q0:DispatchDate"`
+	NumberOfBusinessDays int      `xml:"q0:NumberOfBusinessDays,omitempty"`
+	Carriers             []string `xml:"q0:Carriers"`
+}
+
+type GetPickupAvailabilityResponseEnvelope struct {
+	Reply GetPickupAvailabilityReply `xml:"Body>GetPickupAvailabilityReply"`
+}
+
+func (g *GetPickupAvailabilityResponseEnvelope) Error() error {
+	return g.Reply.replyError("GetPickupAvailability")
+}
+
+func (g *GetPickupAvailabilityResponseEnvelope) Warnings() []Warning {
+	return g.Reply.Warnings()
+}
+
+// GetPickupAvailabilityReply : GetPickupAvailability reply root (`xml:"Body>GetPickupAvailabilityReply"`)
+type GetPickupAvailabilityReply struct {
+	Reply
+	RequestTimestamp Timestamp
+	Options          []PickupScheduleOption
+	CloseTimeType    string
+	CloseTime        string
+	LocalTime        string
+}
+
+// PickupScheduleOption is a day a carrier can pick up at the address. Times
+// are local to the address.
+type PickupScheduleOption struct {
+	Carrier     string
+	Description string
+	// ScheduleDay is SAME_DAY or FUTURE_DAY
+	ScheduleDay string
+	Available   bool
+	PickupDate  string
+	// CutOffTime is the latest time the pickup can be requested that day
+	CutOffTime string
+	// AccessTime is the least time between the ready time and the close
+	// time, as an xs:duration like PT4H30M
+	AccessTime           string
+	ResidentialAvailable bool
+	CountryRelationship  string
+}
+
+// AvailableOptions returns the options that are available, in date order
+func (g *GetPickupAvailabilityReply) AvailableOptions() []PickupScheduleOption {
+	options := []PickupScheduleOption{}
+	for _, option := range g.Options {
+		if option.Available {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// Date returns the pickup date in location
+func (p PickupScheduleOption) Date(location *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", p.PickupDate, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse pickup date %s: %w", p.PickupDate, err)
+	}
+	return date, nil
+}
+
+// CutOff returns the latest time the pickup can be requested, in location
+func (p PickupScheduleOption) CutOff(location *time.Location) (time.Time, error) {
+	date, err := p.Date(location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	cutOff, err := time.Parse("15:04:05", p.CutOffTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse cut off time %s: %w", p.CutOffTime, err)
+	}
+	// Adding the time to midnight would be an hour off on days changing to or
+	// from DST
+	return time.Date(date.Year(), date.Month(), date.Day(), cutOff.Hour(), cutOff.Minute(), cutOff.Second(), 0, location), nil
+}
+
+var accessTimeRegex = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// AccessDuration returns AccessTime as a time.Duration
+func (p PickupScheduleOption) AccessDuration() (time.Duration, error) {
+	matches := accessTimeRegex.FindStringSubmatch(p.AccessTime)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid access time %s", p.AccessTime)
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	duration := time.Duration(0)
+	for idx, unit := range units {
+		if matches[idx+1] == "" {
+			continue
+		}
+		value, err := strconv.Atoi(matches[idx+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid access time %s: %w", p.AccessTime, err)
+		}
+		duration += time.Duration(value) * unit
+	}
+	return duration, nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPickupScheduleOptionCutOff(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// DST ends on 2020-11-01, which has 25 hours
+	option := PickupScheduleOption{PickupDate: "2020-11-01", CutOffTime: "15:00:30"}
+	cutOff, err := option.CutOff(losAngeles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2020, 11, 1, 15, 0, 30, 0, losAngeles); !cutOff.Equal(expected) {
+		t.Fatal("expected", expected, "got", cutOff)
+	}
+}
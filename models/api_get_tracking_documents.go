@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+type GetTrackingDocumentsBody struct {
+	GetTrackingDocumentsRequest GetTrackingDocumentsRequest `xml:"q0:GetTrackingDocumentsRequest"`
+}
+
+type GetTrackingDocumentsRequest struct {
+	Request
+	SelectionDetails              []SelectionDetails            `xml:"q0:SelectionDetails"`
+	TrackingDocumentSpecification TrackingDocumentSpecification `xml:"q0:TrackingDocumentSpecification"`
+}
+
+type TrackingDocumentSpecification struct {
+	DocumentTypes                        []string                              `xml:"q0:DocumentTypes"`
+	SignatureProofOfDeliveryLetterDetail *SignatureProofOfDeliveryLetterDetail `xml:"q0:SignatureProofOfDeliveryLetterDetail,omitempty"`
+}
+
+type SignatureProofOfDeliveryLetterDetail struct {
+	DocumentFormat TrackingDocumentFormat `xml:"q0:DocumentFormat"`
+}
+
+type TrackingDocumentFormat struct {
+	ImageType string `xml:"q0:ImageType"`
+}
+
+type GetTrackingDocumentsResponseEnvelope struct {
+	Reply GetTrackingDocumentsReply `xml:"Body>GetTrackingDocumentsReply"`
+}
+
+func (g *GetTrackingDocumentsResponseEnvelope) Error() error {
+	return g.Reply.replyError("GetTrackingDocuments")
+}
+
+func (g *GetTrackingDocumentsResponseEnvelope) Warnings() []Warning {
+	return g.Reply.Warnings()
+}
+
+// GetTrackingDocumentsReply : GetTrackingDocuments reply root (`xml:"Body>GetTrackingDocumentsReply"`)
+type GetTrackingDocumentsReply struct {
+	Reply
+	Documents []TrackingDocument
+}
+
+type TrackingDocument struct {
+	DocumentType string
+	Grouping     string
+	ImageType    string
+	// Images are base64 encoded
+	Images []string
+}
+
+// SignatureProofOfDeliveryDataAndImageType returns the decoded signature proof
+// of delivery letter and its image type, PDF or PNG
+func (g *GetTrackingDocumentsReply) SignatureProofOfDeliveryDataAndImageType() ([]byte, string, error) {
+	for _, document := range g.Documents {
+		if document.DocumentType != DocumentTypeSignatureProofOfDelivery || len(document.Images) == 0 {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(document.Images[0])
+		if err != nil {
+			return nil, "", fmt.Errorf("decode signature proof of delivery: %w", err)
+		}
+		return data, document.ImageType, nil
+	}
+	return nil, "", errors.New("no signature proof of delivery")
+}
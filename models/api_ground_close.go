@@ -0,0 +1,139 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+type GroundCloseBody struct {
+	GroundCloseRequest GroundCloseRequest `xml:"q0:GroundCloseRequest"`
+}
+
+type GroundCloseRequest struct {
+	Request
+	TimeUpToWhichShipmentsAreToBeClosed Timestamp `xml:"q0:TimeUpToWhichShipmentsAreToBeClosed"`
+}
+
+type GroundCloseWithDocumentsBody struct {
+	GroundCloseWithDocumentsRequest GroundCloseWithDocumentsRequest `xml:"q0:GroundCloseWithDocumentsRequest"`
+}
+
+type GroundCloseWithDocumentsRequest struct {
+	Request
+	CloseDate                  string                     `xml:"q0:CloseDate"`
+	CloseDocumentSpecification CloseDocumentSpecification `xml:"q0:CloseDocumentSpecification"`
+}
+
+type CloseDocumentSpecification struct {
+	CloseDocumentTypes []string `xml:"q0:CloseDocumentTypes"`
+}
+
+type GroundCloseResponseEnvelope struct {
+	Reply GroundCloseReply `xml:"Body>GroundCloseReply"`
+}
+
+func (g *GroundCloseResponseEnvelope) Error() error {
+	return g.Reply.replyError("GroundClose")
+}
+
+func (g *GroundCloseResponseEnvelope) Warnings() []Warning {
+	return g.Reply.Warnings()
+}
+
+type GroundCloseDocumentsResponseEnvelope struct {
+	Reply GroundCloseDocumentsReply `xml:"Body>GroundCloseDocumentsReply"`
+}
+
+func (g *GroundCloseDocumentsResponseEnvelope) Error() error {
+	return g.Reply.replyError("GroundCloseWithDocuments")
+}
+
+func (g *GroundCloseDocumentsResponseEnvelope) Warnings() []Warning {
+	return g.Reply.Warnings()
+}
+
+// GroundCloseReply : GroundClose reply root (`xml:"Body>GroundCloseReply"`)
+type GroundCloseReply struct {
+	Reply
+	// The reports and the manifest file are base64 encoded, and empty when
+	// there is none
+	CodReport         string
+	HazMatCertificate string
+	Manifest          struct {
+		FileName string
+		File     string
+	}
+	MultiweightReport string
+}
+
+// GroundCloseDocumentsReply : GroundCloseWithDocuments reply root (`xml:"Body>GroundCloseDocumentsReply"`)
+type GroundCloseDocumentsReply struct {
+	Reply
+	CloseDocuments []CloseDocument
+}
+
+type CloseDocument struct {
+	// Type is one of the CloseDocumentType constants
+	Type                        string
+	ShippingDocumentDisposition string
+	Resolution                  int
+	CopiesToPrint               int
+	Parts                       []CloseDocumentPart
+}
+
+type CloseDocumentPart struct {
+	DocumentPartSequenceNumber int
+	// Image is base64 encoded
+	Image string
+}
+
+// CloseReport is a decoded manifest or report of a close
+type CloseReport struct {
+	// Type is one of the CloseDocumentType constants
+	Type string
+	// FileName is only set for manifests of ground closes
+	FileName string
+	Data     []byte
+}
+
+// Reports returns the decoded manifest and reports of the close
+func (g *GroundCloseReply) Reports() ([]CloseReport, error) {
+	reports := []CloseReport{}
+	encodedReports := []struct {
+		documentType, fileName, data string
+	}{
+		{CloseDocumentTypeManifest, g.Manifest.FileName, g.Manifest.File},
+		{CloseDocumentTypeCODReport, "", g.CodReport},
+		{CloseDocumentTypeOP950, "", g.HazMatCertificate},
+		{CloseDocumentTypeMultiweightReport, "", g.MultiweightReport},
+	}
+	for _, encoded := range encodedReports {
+		if encoded.data == "" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded.data)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", encoded.documentType, err)
+		}
+		reports = append(reports, CloseReport{Type: encoded.documentType, FileName: encoded.fileName, Data: data})
+	}
+	return reports, nil
+}
+
+// Reports returns the decoded documents of the close, with the parts of each
+// document concatenated
+func (g *GroundCloseDocumentsReply) Reports() ([]CloseReport, error) {
+	reports := []CloseReport{}
+	for _, document := range g.CloseDocuments {
+		report := CloseReport{Type: document.Type}
+		for _, part := range document.Parts {
+			data, err := base64.StdEncoding.DecodeString(part.Image)
+			if err != nil {
+				return nil, fmt.Errorf("decode part %d of %s: %w", part.DocumentPartSequenceNumber, document.Type, err)
+			}
+			report.Data = append(report.Data, data...)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
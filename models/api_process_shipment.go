@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"time"
 )
@@ -19,6 +20,10 @@ type Shipment struct {
 	InvoiceNumber      string
 	RMANumber          string
 
+	// Packages, when set, ships several packages as one multi-piece shipment
+	// instead of a single package of Dimensions
+	Packages []PackageDetail
+
 	// Only used for international ground shipments
 	OriginatorName    string
 	Commodities       Commodities
@@ -251,14 +256,43 @@ func defaultEventNotificationDetail(notificationEmail string) *EventNotification
 }
 
 func (s *Shipment) RequestedPackageLineItems() []RequestedPackageLineItem {
-	return []RequestedPackageLineItem{{
-		SequenceNumber:     1,
-		PhysicalPackaging:  PackagingBag,
-		ItemDescription:    "ItemDescription",
-		CustomerReferences: s.CustomerReferences(),
-		Weight:             s.Weight(),
-		Dimensions:         s.ValidatedDimensions(),
-	}}
+	if len(s.Packages) == 0 {
+		return []RequestedPackageLineItem{{
+			SequenceNumber:     1,
+			PhysicalPackaging:  PackagingBag,
+			ItemDescription:    "ItemDescription",
+			CustomerReferences: s.CustomerReferences(),
+			Weight:             s.Weight(),
+			Dimensions:         s.ValidatedDimensions(),
+		}}
+	}
+
+	items := make([]RequestedPackageLineItem, len(s.Packages))
+	for idx, pkg := range s.Packages {
+		item := RequestedPackageLineItem{
+			SequenceNumber:     idx + 1,
+			InsuredValue:       pkg.DeclaredValue,
+			PhysicalPackaging:  PackagingBag,
+			ItemDescription:    "ItemDescription",
+			CustomerReferences: s.CustomerReferences(),
+			Weight:             pkg.Weight,
+			Dimensions:         pkg.Dimensions,
+		}
+		for _, reference := range pkg.References {
+			item.CustomerReferences = append(item.CustomerReferences, CustomerReference{
+				CustomerReferenceType: CustomerReferenceTypeCustomerReference,
+				Value:                 sanitizeReferenceForFedexAPI(reference),
+			})
+		}
+		if item.Weight.IsZero() {
+			item.Weight = s.Weight()
+		}
+		if !item.Dimensions.IsValid() {
+			item.Dimensions = s.ValidatedDimensions()
+		}
+		items[idx] = item
+	}
+	return items
 }
 
 type ProcessShipmentBody struct {
@@ -275,15 +309,22 @@ type ShipResponseEnvelope struct {
 }
 
 func (s *ShipResponseEnvelope) Error() error {
-	return s.Reply.Error()
+	return s.Reply.replyError("ProcessShipment")
+}
+
+func (s *ShipResponseEnvelope) Warnings() []Warning {
+	return s.Reply.Warnings()
 }
 
 // ProcessShipReply : Process shipment reply root (`xml:"Body>ProcessShipmentReply"`)
 type ProcessShipmentReply struct {
 	Reply
-	TransactionDetail       TransactionDetail
 	CompletedShipmentDetail CompletedShipmentDetail
 	Events                  []Event
+
+	// ChildReplies are the replies of the packages shipped after the master
+	// package of a multi-piece shipment, in order
+	ChildReplies []ProcessShipmentReply `xml:"-"`
 }
 
 func (p *ProcessShipmentReply) LabelDataAndImageType() ([]byte, string, error) {
@@ -293,6 +334,43 @@ func (p *ProcessShipmentReply) LabelDataAndImageType() ([]byte, string, error) {
 	return nil, "", errors.New("no label")
 }
 
+// PackageDetails returns the completed package of the master package, then
+// the ones of the child packages
+func (p *ProcessShipmentReply) PackageDetails() []CompletedPackageDetails {
+	packageDetails := []CompletedPackageDetails{p.CompletedShipmentDetail.CompletedPackageDetails}
+	for _, childReply := range p.ChildReplies {
+		packageDetails = append(packageDetails, childReply.CompletedShipmentDetail.CompletedPackageDetails)
+	}
+	return packageDetails
+}
+
+// TrackingNumbers returns the tracking number of every package, master first
+func (p *ProcessShipmentReply) TrackingNumbers() []string {
+	trackingNumbers := []string{}
+	for _, packageDetail := range p.PackageDetails() {
+		for _, trackingID := range packageDetail.TrackingIds {
+			trackingNumbers = append(trackingNumbers, trackingID.TrackingNumber)
+		}
+	}
+	return trackingNumbers
+}
+
+// LabelsDataAndImageType returns the label of every package, master first,
+// like LabelDataAndImageType
+func (p *ProcessShipmentReply) LabelsDataAndImageType() ([][]byte, string, error) {
+	labels := [][]byte{}
+	imageType := ""
+	for _, packageDetail := range p.PackageDetails() {
+		label := packageDetail.Label
+		if len(label.Parts) == 0 {
+			return nil, "", fmt.Errorf("no label for package %s", packageDetail.SequenceNumber)
+		}
+		labels = append(labels, []byte(label.Parts[0].Image))
+		imageType = label.ImageType
+	}
+	return labels, imageType, nil
+}
+
 func (p *ProcessShipmentReply) CommercialInvoiceDataAndImageType() ([]byte, string, error) {
 	for _, document := range p.CompletedShipmentDetail.ShipmentDocuments {
 		if document.Type == DocumentTypeCommercialInvoice && len(document.Parts) > 0 {
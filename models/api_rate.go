@@ -13,6 +13,9 @@ type Rate struct {
 
 	Service     string
 	Commodities Commodities
+
+	// Packages, when set, rates several packages instead of a single bag
+	Packages []PackageDetail
 }
 
 func (r *Rate) ServiceType() string {
@@ -77,13 +80,48 @@ func (r *Rate) Weight() Weight {
 	return Weight{Units: WeightUnitsLB, Value: 13}
 }
 
+func (r *Rate) RequestedPackageLineItems() []RequestedPackageLineItem {
+	packages := r.Packages
+	if len(packages) == 0 {
+		packages = []PackageDetail{{}}
+	}
+
+	items := make([]RequestedPackageLineItem, len(packages))
+	for idx, pkg := range packages {
+		item := RequestedPackageLineItem{
+			SequenceNumber:    idx + 1,
+			GroupPackageCount: 1,
+			InsuredValue:      pkg.DeclaredValue,
+			Weight:            pkg.Weight,
+			Dimensions:        pkg.Dimensions,
+			PhysicalPackaging: PackagingBag,
+			ItemDescription:   "Stuff",
+			CustomerReferences: []CustomerReference{
+				{
+					CustomerReferenceType: CustomerReferenceTypeCustomerReference,
+					Value:                 CustomerReferenceValueNaftaCoo,
+				},
+			},
+		}
+		if item.Weight.IsZero() {
+			item.Weight = r.Weight()
+		}
+		if !item.Dimensions.IsValid() {
+			item.Dimensions = Dimensions{Length: 5, Width: 5, Height: 5, Units: DimensionsUnitsIn}
+		}
+		items[idx] = item
+	}
+	return items
+}
+
 type RateBody struct {
 	RateRequest RateRequest `xml:"q0:RateRequest"`
 }
 
 type RateRequest struct {
 	Request
-	RequestedShipment RequestedShipment `xml:"q0:RequestedShipment"`
+	ReturnTransitAndCommit bool              `xml:"q0:ReturnTransitAndCommit,omitempty"`
+	RequestedShipment      RequestedShipment `xml:"q0:RequestedShipment"`
 }
 
 type RateResponseEnvelope struct {
@@ -91,14 +129,17 @@ type RateResponseEnvelope struct {
 }
 
 func (r *RateResponseEnvelope) Error() error {
-	return r.Reply.Error()
+	return r.Reply.replyError("Rate")
+}
+
+func (r *RateResponseEnvelope) Warnings() []Warning {
+	return r.Reply.Warnings()
 }
 
 // RateReply : Process shipment reply root (`xml:"Body>RateReply"`)
 type RateReply struct {
 	Reply
-	TransactionDetail TransactionDetail
-	RateReplyDetails  []RateReplyDetail
+	RateReplyDetails []RateReplyDetail
 }
 
 // TotalCost returns the sum of any charges in the reply
@@ -112,24 +153,27 @@ func (rr *RateReply) TotalCost() (Charge, error) {
 }
 
 func (rr *RateReply) firstRatedShipmentDetails() (RateDetail, error) {
+	ratedShipmentDetails := []Rating{}
+	for _, rateReplyDetail := range rr.RateReplyDetails {
+		ratedShipmentDetails = append(ratedShipmentDetails, rateReplyDetail.RatedShipmentDetails...)
+	}
+	return preferredRateDetail(ratedShipmentDetails)
+}
 
+func preferredRateDetail(ratedShipmentDetails []Rating) (RateDetail, error) {
 	// Find the rated shipment detail of type "PREFERRED_ACCOUNT_PACKAGE"
-	for _, rateReplyDetail := range rr.RateReplyDetails {
-		for _, ratedShipmentDetail := range rateReplyDetail.RatedShipmentDetails {
-			if ratedShipmentDetail.ShipmentRateDetail.RateType == RateTypePreferredAccountPackage {
-				return ratedShipmentDetail.ShipmentRateDetail, nil
-			}
+	for _, ratedShipmentDetail := range ratedShipmentDetails {
+		if ratedShipmentDetail.ShipmentRateDetail.RateType == RateTypePreferredAccountPackage {
+			return ratedShipmentDetail.ShipmentRateDetail, nil
 		}
 	}
 
 	// We prefer the rated shipment detail of type "PREFERRED_ACCOUNT_PACKAGE",
 	// but if that isn't found, return the rated shipment detail with RateType
 	// equal to `PAYOR_ACCOUNT_PACKAGE` or `PAYOR_ACCOUNT_SHIPMENT`
-	for _, rateReplyDetail := range rr.RateReplyDetails {
-		for _, ratedShipmentDetail := range rateReplyDetail.RatedShipmentDetails {
-			if strings.HasPrefix(ratedShipmentDetail.ShipmentRateDetail.RateType, "PAYOR_") {
-				return ratedShipmentDetail.ShipmentRateDetail, nil
-			}
+	for _, ratedShipmentDetail := range ratedShipmentDetails {
+		if strings.HasPrefix(ratedShipmentDetail.ShipmentRateDetail.RateType, "PAYOR_") {
+			return ratedShipmentDetail.ShipmentRateDetail, nil
 		}
 	}
 
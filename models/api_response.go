@@ -1,13 +1,10 @@
 package models
 
-import (
-	"fmt"
-)
-
 const (
 	notificationSeverityError   = "ERROR"
+	notificationSeverityFailure = "FAILURE"
 	notificationSeverityNote    = "NOTE"
-	notificationSeverityWarning = "WARNING" // TODO consider doing something with the response if it's a warning
+	notificationSeverityWarning = "WARNING"
 	notificationSeveritySuccess = "SUCCESS"
 )
 
@@ -17,25 +14,72 @@ type Response interface {
 
 // Reply has common stuff on all responses from FedEx API
 type Reply struct {
-	HighestSeverity string
-	Notifications   []Notification
-	Version         VersionResponse
-	JobID           string `xml:"JobId"`
+	HighestSeverity   string
+	Notifications     []Notification
+	TransactionDetail TransactionDetail
+	Version           VersionResponse
+	JobID             string `xml:"JobId"`
 }
 
+// Error returns a *ReplyError if the reply failed
 func (r Reply) Error() error {
+	return r.replyError("")
+}
+
+func (r Reply) replyError(operation string) error {
 	if r.HighestSeverity == notificationSeveritySuccess ||
 		r.HighestSeverity == notificationSeverityNote ||
 		r.HighestSeverity == notificationSeverityWarning {
 		return nil
 	}
 
-	for _, notification := range r.Notifications {
-		if notification.Severity == r.HighestSeverity {
-			return fmt.Errorf("reply got error: %s", notification.Message)
+	return &ReplyError{
+		Operation:       operation,
+		TransactionID:   r.TransactionDetail.CustomerTransactionID,
+		HighestSeverity: r.HighestSeverity,
+		Notifications:   r.Notifications,
+	}
+}
+
+// Warnings returns the WARNING and NOTE notifications of the reply. FedEx
+// still succeeds with these, e.g. after correcting an address or truncating a
+// label reference.
+func (r Reply) Warnings() []Warning {
+	return warnings(r.Notifications)
+}
+
+func warnings(notifications []Notification) []Warning {
+	var warnings []Warning
+	for _, notification := range notifications {
+		if notification.Severity == notificationSeverityWarning ||
+			notification.Severity == notificationSeverityNote {
+			warnings = append(warnings, Warning(notification))
+		}
+	}
+	return warnings
+}
+
+// WarningError returns a *ReplyError made of the warnings with one of the
+// given codes, or nil if there are none
+func WarningError(operation string, warnings []Warning, codes []string) error {
+	var promoted []Notification
+	for _, warning := range warnings {
+		for _, code := range codes {
+			if warning.Code == code {
+				promoted = append(promoted, Notification(warning))
+				break
+			}
 		}
 	}
-	return fmt.Errorf("reply got status: %s", r.HighestSeverity)
+	if len(promoted) == 0 {
+		return nil
+	}
+
+	return &ReplyError{
+		Operation:       operation,
+		HighestSeverity: promoted[0].Severity,
+		Notifications:   promoted,
+	}
 }
 
 type Notification struct {
@@ -46,6 +90,14 @@ type Notification struct {
 	LocalizedMessage string
 }
 
+// Warning is a WARNING or NOTE notification
+type Warning Notification
+
+// IsNote reports whether the warning is only informational
+func (w Warning) IsNote() bool {
+	return w.Severity == notificationSeverityNote
+}
+
 type VersionResponse struct {
 	ServiceID    string `xml:"ServiceId"`
 	Major        int
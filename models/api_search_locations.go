@@ -0,0 +1,241 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+type SearchLocationsBody struct {
+	SearchLocationsRequest SearchLocationsRequest `xml:"q0:SearchLocationsRequest"`
+}
+
+type SearchLocationsRequest struct {
+	Request
+	EffectiveDate            string                    `xml:"q0:EffectiveDate"`
+	LocationsSearchCriterion string                    `xml:"q0:LocationsSearchCriterion"`
+	Address                  *Address                  `xml:"q0:Address,omitempty"`
+	GeographicCoordinates    string                    `xml:"q0:GeographicCoordinates,omitempty"`
+	MultipleMatchesAction    string                    `xml:"q0:MultipleMatchesAction"`
+	SortDetail               LocationSortDetail        `xml:"q0:SortDetail"`
+	Constraints              SearchLocationConstraints `xml:"q0:Constraints"`
+}
+
+type LocationSortDetail struct {
+	Criterion string `xml:"q0:Criterion"`
+	Order     string `xml:"q0:Order"`
+}
+
+type SearchLocationConstraints struct {
+	RadiusDistance         Distance `xml:"q0:RadiusDistance"`
+	ResultsRequested       int      `xml:"q0:ResultsRequested,omitempty"`
+	LocationTypesToInclude []string `xml:"q0:LocationTypesToInclude"`
+}
+
+type Distance struct {
+	Value float64 `xml:"q0:Value"`
+	Units string  `xml:"q0:Units"`
+}
+
+type SearchLocationsResponseEnvelope struct {
+	Reply SearchLocationsReply `xml:"Body>SearchLocationsReply"`
+}
+
+func (s *SearchLocationsResponseEnvelope) Error() error {
+	return s.Reply.replyError("SearchLocations")
+}
+
+func (s *SearchLocationsResponseEnvelope) Warnings() []Warning {
+	return s.Reply.Warnings()
+}
+
+// SearchLocationsReply : SearchLocations reply root (`xml:"Body>SearchLocationsReply"`)
+type SearchLocationsReply struct {
+	Reply
+	TotalResultsAvailable          int
+	ResultsReturned                int
+	AddressToLocationRelationships []AddressToLocationRelationship
+}
+
+// AddressToLocationRelationship has the locations near an address FedEx
+// matched the searched address to
+type AddressToLocationRelationship struct {
+	MatchedAddress             AddressReply
+	DistanceAndLocationDetails []DistanceAndLocationDetail
+}
+
+type DistanceAndLocationDetail struct {
+	Distance       DistanceReply
+	LocationDetail LocationDetail
+}
+
+// DistanceReply is a Distance in a reply, see AddressReply
+type DistanceReply struct {
+	Value float64
+	Units string
+}
+
+type LocationDetail struct {
+	LocationId                string
+	StoreNumber               string
+	LocationContactAndAddress struct {
+		Contact ContactReply
+		Address AddressReply
+	}
+	GeographicCoordinates string
+	// LocationType is one of the LocationType constants
+	LocationType string
+	// Attributes are what the location offers, like ACCEPTS_CASH,
+	// DROP_BOX or WEEKEND_SERVICES
+	Attributes           []string
+	LocationCapabilities []LocationCapability
+	NormalHours          []LocationHours
+}
+
+// LocationCapability is a service of a carrier the location handles
+type LocationCapability struct {
+	CarrierCode     string
+	ServiceType     string
+	ServiceCategory string
+	// TransferOfPossessionType is DROPOFF when packages can be dropped off at
+	// the location, or HOLD_AT_LOCATION, REDIRECT_TO_HOLD_AT_LOCATION...
+	TransferOfPossessionType string
+	DaysOfWeek               []string
+}
+
+// LocationHours are the opening hours of a location on a day of the week
+type LocationHours struct {
+	DayofWeek string
+	// OperationalHoursType is OPEN_BY_HOURS, OPEN_ALL_DAY or CLOSED_ALL_DAY
+	OperationalHoursType string
+	Hours                []LocationTimeRange
+}
+
+// LocationTimeRange is a range of local times like 09:00:00
+type LocationTimeRange struct {
+	Begins string
+	Ends   string
+}
+
+// Location is a FedEx location near the searched address or coordinates
+type Location struct {
+	ID          string
+	StoreNumber string
+	Type        string
+	Contact     Contact
+	Address     Address
+	// Distance is from the searched address or coordinates
+	Distance    Distance
+	Coordinates *GeographicCoordinates
+	Hours       []LocationHours
+	// Capabilities are the carrier services the location handles
+	Capabilities []LocationCapability
+	Attributes   []string
+	// AcceptsSmartPostReturns is true when SmartPost packages, like returns,
+	// can be dropped off at the location
+	AcceptsSmartPostReturns bool
+}
+
+// Locations returns the locations of the reply, nearest first. FedEx may
+// match the searched address to several addresses, so a location near more
+// than one of them is only returned once, at its nearest.
+func (s *SearchLocationsReply) Locations() []Location {
+	locations := []Location{}
+	indexes := map[string]int{}
+	for _, relationship := range s.AddressToLocationRelationships {
+		for _, detail := range relationship.DistanceAndLocationDetails {
+			location := detail.Location()
+			idx, ok := indexes[location.ID]
+			switch {
+			case !ok || location.ID == "":
+				indexes[location.ID] = len(locations)
+				locations = append(locations, location)
+			case location.Distance.Value < locations[idx].Distance.Value:
+				locations[idx] = location
+			}
+		}
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].Distance.Value < locations[j].Distance.Value
+	})
+	return locations
+}
+
+// Location returns d as a Location. Its Coordinates are nil when FedEx gave
+// none, or coordinates that can't be parsed.
+func (d DistanceAndLocationDetail) Location() Location {
+	detail := d.LocationDetail
+	location := Location{
+		ID:           detail.LocationId,
+		StoreNumber:  detail.StoreNumber,
+		Type:         detail.LocationType,
+		Contact:      detail.LocationContactAndAddress.Contact.Contact(),
+		Address:      detail.LocationContactAndAddress.Address.Address(),
+		Distance:     Distance{Value: d.Distance.Value, Units: d.Distance.Units},
+		Hours:        detail.NormalHours,
+		Capabilities: detail.LocationCapabilities,
+		Attributes:   detail.Attributes,
+	}
+
+	if coordinates, err := ParseGeographicCoordinates(detail.GeographicCoordinates); err == nil {
+		location.Coordinates = &coordinates
+	}
+
+	for _, capability := range detail.LocationCapabilities {
+		if capability.CarrierCode == CarrierCodeFXSP && capability.TransferOfPossessionType == TransferOfPossessionTypeDropoff {
+			location.AcceptsSmartPostReturns = true
+		}
+	}
+	return location
+}
+
+// GeographicCoordinates are a latitude and a longitude in degrees
+type GeographicCoordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// String formats g in ISO 6709, like +34.0195-118.4912/, as FedEx expects
+func (g GeographicCoordinates) String() string {
+	return fmt.Sprintf("%+08.4f%+09.4f/", g.Latitude, g.Longitude)
+}
+
+var geographicCoordinatesRegex = regexp.MustCompile(`^([+-]\d+(?:\.\d+)?)([+-]\d+(?:\.\d+)?)/?$`)
+
+// ParseGeographicCoordinates parses ISO 6709 coordinates in degrees, like
+// +34.0195-118.4912/
+func ParseGeographicCoordinates(s string) (GeographicCoordinates, error) {
+	matches := geographicCoordinatesRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return GeographicCoordinates{}, fmt.Errorf("invalid geographic coordinates %s", s)
+	}
+
+	latitude, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return GeographicCoordinates{}, fmt.Errorf("invalid latitude %s: %w", matches[1], err)
+	}
+	longitude, err := strconv.ParseFloat(matches[2], 64)
+	if err != nil {
+		return GeographicCoordinates{}, fmt.Errorf("invalid longitude %s: %w", matches[2], err)
+	}
+	return GeographicCoordinates{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// LocationSearch is what to search locations around
+type LocationSearch struct {
+	// Address or Coordinates is the center of the search. Coordinates are
+	// used when both are set.
+	Address     *Address
+	Coordinates *GeographicCoordinates
+	// Radius is the search radius, in RadiusUnits, which defaults to miles
+	Radius      float64
+	RadiusUnits string
+	// LocationTypes are the LocationType constants to include, all of them
+	// when empty
+	LocationTypes []string
+	// MaxResults is how many locations to return at most, FedEx's default
+	// when 0
+	MaxResults int
+}
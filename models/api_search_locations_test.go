@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestSearchLocationsReplyLocations(t *testing.T) {
+	detail := func(id string, distance float64, coordinates string) DistanceAndLocationDetail {
+		return DistanceAndLocationDetail{
+			Distance:       DistanceReply{Value: distance, Units: DistanceUnitsMI},
+			LocationDetail: LocationDetail{LocationId: id, GeographicCoordinates: coordinates},
+		}
+	}
+	reply := SearchLocationsReply{
+		AddressToLocationRelationships: []AddressToLocationRelationship{
+			{DistanceAndLocationDetails: []DistanceAndLocationDetail{
+				detail("SMOA", 0.4, "+34.0195-118.4912/"),
+				detail("LAXR", 2.5, "+34.0522-118.2437/"),
+			}},
+			{DistanceAndLocationDetails: []DistanceAndLocationDetail{
+				detail("LAXR", 1.2, "+34.0522-118.2437/"),
+				detail("VNYA", 0.8, "not coordinates"),
+			}},
+		},
+	}
+
+	locations := reply.Locations()
+	if len(locations) != 3 {
+		t.Fatal("locations should be deduplicated", locations)
+	}
+	ids := []string{"SMOA", "VNYA", "LAXR"}
+	for idx, location := range locations {
+		if location.ID != ids[idx] {
+			t.Fatal("locations should be nearest first", locations)
+		}
+	}
+	if locations[2].Distance.Value != 1.2 {
+		t.Fatal("duplicate locations should be at their nearest", locations[2].Distance)
+	}
+	if locations[0].Coordinates == nil || locations[1].Coordinates != nil {
+		t.Fatal("only invalid coordinates should be skipped", locations[0].Coordinates, locations[1].Coordinates)
+	}
+}
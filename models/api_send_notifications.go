@@ -8,6 +8,7 @@ type SendNotificationsBody struct {
 type SendNotificationsRequest struct {
 	Request
 	TrackingNumber         string `xml:"q0:TrackingNumber"`
+	PagingToken            string `xml:"q0:PagingToken,omitempty"`
 	TrackingNumberUniqueID string `xml:"q0:TrackingNumberUniqueId"`
 	// ShipDateRangeBegin      DateOrTimestamp          `xml:"q0:ShipDateRangeBegin"` // Don't bother with these for now
 	// ShipDateRangeEnd        DateOrTimestamp          `xml:"q0:ShipDateRangeEnd"`
@@ -21,7 +22,11 @@ type SendNotificationsResponseEnvelope struct {
 }
 
 func (s *SendNotificationsResponseEnvelope) Error() error {
-	return s.Reply.Error()
+	return s.Reply.replyError("SendNotifications")
+}
+
+func (s *SendNotificationsResponseEnvelope) Warnings() []Warning {
+	return s.Reply.Warnings()
 }
 
 // SendNotificationsReply : CreatePickup reply root (`xml:"Body>SendNotificationsReply"`)
@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+type ServiceAvailabilityBody struct {
+	ServiceAvailabilityRequest ServiceAvailabilityRequest `xml:"q0:ServiceAvailabilityRequest"`
+}
+
+type ServiceAvailabilityRequest struct {
+	Request
+	Origin      Address `xml:"q0:Origin"`
+	Destination Address `xml:"q0:Destination"`
+	ShipDate    string  `xml:"q0:ShipDate"`
+	Packaging   string  `xml:"q0:Packaging,omitempty"`
+}
+
+type ServiceAvailabilityResponseEnvelope struct {
+	Reply ServiceAvailabilityReply `xml:"Body>ServiceAvailabilityReply"`
+}
+
+func (s *ServiceAvailabilityResponseEnvelope) Error() error {
+	return s.Reply.replyError("ServiceAvailability")
+}
+
+func (s *ServiceAvailabilityResponseEnvelope) Warnings() []Warning {
+	return s.Reply.Warnings()
+}
+
+// ServiceAvailabilityReply : ServiceAvailability reply root (`xml:"Body>ServiceAvailabilityReply"`)
+type ServiceAvailabilityReply struct {
+	Reply
+	Options []ServiceAvailabilityOption
+}
+
+// ServiceAvailabilityOption is a service available between the origin and
+// the destination
+type ServiceAvailabilityOption struct {
+	Service string
+	// DeliveryDate and DeliveryDay are when FedEx commits to deliver, which
+	// ground services usually don't
+	DeliveryDate         string
+	DeliveryDay          string
+	DestinationStationID string `xml:"DestinationStationId"`
+	DestinationAirportID string `xml:"DestinationAirportId"`
+	// TransitTime and MaximumTransitTime are like ONE_DAY or TWO_DAYS
+	TransitTime        string
+	MaximumTransitTime string
+}
+
+// ServiceCommitment is an available service, normalized from a
+// ServiceAvailabilityOption
+type ServiceCommitment struct {
+	ServiceType string
+	// TransitDays and MaximumTransitDays are the business days in transit,
+	// zero when FedEx doesn't say
+	TransitDays        int
+	MaximumTransitDays int
+	// Commit is the day FedEx commits to deliver, or else the ship date plus
+	// the business days in transit. It's nil when neither is known.
+	Commit *time.Time
+	// DayOfWeek is the day of Commit, like MON
+	DayOfWeek string
+}
+
+// ServiceCommitments are the commitments of the available services
+type ServiceCommitments []ServiceCommitment
+
+// Commitments returns a commitment per available service, for packages
+// shipped on shipDate. A delivery date that can't be parsed is ignored, like
+// a missing one.
+func (s *ServiceAvailabilityReply) Commitments(shipDate time.Time) ServiceCommitments {
+	commitments := ServiceCommitments{}
+	for _, option := range s.Options {
+		commitment := ServiceCommitment{
+			ServiceType:        option.Service,
+			TransitDays:        transitDays[option.TransitTime],
+			MaximumTransitDays: transitDays[option.MaximumTransitTime],
+			DayOfWeek:          option.DeliveryDay,
+		}
+
+		var commit time.Time
+		if date, err := time.ParseInLocation("2006-01-02", option.DeliveryDate, shipDate.Location()); err == nil {
+			commit = date
+		} else if latest := commitment.latestTransitDays(); latest > 0 {
+			commit = addBusinessDays(shipDate, latest)
+		}
+		if !commit.IsZero() {
+			commitment.Commit = &commit
+			if commitment.DayOfWeek == "" {
+				commitment.DayOfWeek = dayOfWeek(commit)
+			}
+		}
+
+		commitments = append(commitments, commitment)
+	}
+	return commitments
+}
+
+// latestTransitDays returns the most business days the package may be in
+// transit
+func (c ServiceCommitment) latestTransitDays() int {
+	if c.MaximumTransitDays > c.TransitDays {
+		return c.MaximumTransitDays
+	}
+	return c.TransitDays
+}
+
+// dayOfWeek returns the FedEx day of week of t, like MON
+func dayOfWeek(t time.Time) string {
+	return []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}[t.Weekday()]
+}
+
+// Find returns the commitment of serviceType, or nil if it isn't available
+func (c ServiceCommitments) Find(serviceType string) *ServiceCommitment {
+	for idx := range c {
+		if c[idx].ServiceType == serviceType {
+			return &c[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServiceAvailabilityReplyCommitments(t *testing.T) {
+	friday := time.Date(2020, time.October, 16, 0, 0, 0, 0, time.UTC)
+	reply := ServiceAvailabilityReply{
+		Options: []ServiceAvailabilityOption{
+			{Service: "STANDARD_OVERNIGHT", DeliveryDate: "2020-10-19", DeliveryDay: "MON"},
+			{Service: ServiceTypeFedexGround, TransitTime: "ONE_DAY", MaximumTransitTime: "TWO_DAYS"},
+			{Service: "PRIORITY_OVERNIGHT", DeliveryDate: "next monday", TransitTime: "ONE_DAY"},
+			{Service: "FEDEX_2_DAY", DeliveryDate: "not a date"},
+		},
+	}
+
+	tests := []struct {
+		serviceType string
+		commit      *time.Time
+		dayOfWeek   string
+	}{
+		{"STANDARD_OVERNIGHT", timePtr(time.Date(2020, time.October, 19, 0, 0, 0, 0, time.UTC)), "MON"},
+		{ServiceTypeFedexGround, timePtr(time.Date(2020, time.October, 20, 0, 0, 0, 0, time.UTC)), "TUE"},
+		{"PRIORITY_OVERNIGHT", timePtr(time.Date(2020, time.October, 19, 0, 0, 0, 0, time.UTC)), "MON"},
+		{"FEDEX_2_DAY", nil, ""},
+	}
+
+	commitments := reply.Commitments(friday)
+	if len(commitments) != len(tests) {
+		t.Fatal("should have a commitment per option, even with an invalid delivery date", commitments)
+	}
+	for _, test := range tests {
+		commitment := commitments.Find(test.serviceType)
+		if commitment == nil {
+			t.Fatal("should have a commitment for", test.serviceType)
+		}
+		if (commitment.Commit == nil) != (test.commit == nil) ||
+			(test.commit != nil && !commitment.Commit.Equal(*test.commit)) ||
+			commitment.DayOfWeek != test.dayOfWeek {
+			t.Fatal("commitment doesn't match", test.serviceType, commitment.Commit, commitment.DayOfWeek)
+		}
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
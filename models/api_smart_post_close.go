@@ -0,0 +1,28 @@
+package models
+
+type SmartPostCloseBody struct {
+	SmartPostCloseRequest SmartPostCloseRequest `xml:"q0:SmartPostCloseRequest"`
+}
+
+type SmartPostCloseRequest struct {
+	Request
+	HubID         string `xml:"q0:HubId"`
+	PickUpCarrier string `xml:"q0:PickUpCarrier"`
+}
+
+type SmartPostCloseResponseEnvelope struct {
+	Reply SmartPostCloseReply `xml:"Body>SmartPostCloseReply"`
+}
+
+func (s *SmartPostCloseResponseEnvelope) Error() error {
+	return s.Reply.replyError("SmartPostClose")
+}
+
+func (s *SmartPostCloseResponseEnvelope) Warnings() []Warning {
+	return s.Reply.Warnings()
+}
+
+// SmartPostCloseReply : SmartPostClose reply root (`xml:"Body>SmartPostCloseReply"`)
+type SmartPostCloseReply struct {
+	Reply
+}
@@ -11,8 +11,8 @@ type TrackBody struct {
 
 type TrackRequest struct {
 	Request
-	SelectionDetails  SelectionDetails `xml:"q0:SelectionDetails"`
-	ProcessingOptions string           `xml:"q0:ProcessingOptions"`
+	SelectionDetails  []SelectionDetails `xml:"q0:SelectionDetails"`
+	ProcessingOptions string             `xml:"q0:ProcessingOptions"`
 }
 
 type TrackResponseEnvelope struct {
@@ -24,16 +24,16 @@ func (t *TrackResponseEnvelope) Error() error {
 	// doesn't say it errored, even though the Reply.CompletedTrackDetails does
 
 	// Error if Reply has error
-	err := t.Reply.Error()
+	err := t.Reply.replyError("Track")
 	if err != nil {
-		return fmt.Errorf("track reply error: %s", err)
+		return fmt.Errorf("track reply error: %w", err)
 	}
 
 	// Error if CompletedTrackDetails has error
 	for _, completedTrackDetail := range t.Reply.CompletedTrackDetails {
 		for _, trackDetail := range completedTrackDetail.TrackDetails {
-			if trackDetail.Notification.Severity == notificationSeverityError {
-				return fmt.Errorf("track detail error: %s", trackDetail.Notification.Message)
+			if err := trackDetail.replyError(t.Reply.TransactionDetail.CustomerTransactionID); err != nil {
+				return fmt.Errorf("track detail error: %w", err)
 			}
 		}
 	}
@@ -41,67 +41,198 @@ func (t *TrackResponseEnvelope) Error() error {
 	return nil
 }
 
-// TrackReply : Track reply root (`xml:"Body>TrackReply"`)
-type TrackReply struct {
-	Reply
-	CompletedTrackDetails []CompletedTrackDetail
+// TrackManyResponseEnvelope is the response to a track request for several
+// tracking numbers. Unlike TrackResponseEnvelope, it only errors when the
+// whole reply does, the errors of each tracking number are returned by
+// CompletedTrackDetail.Error.
+type TrackManyResponseEnvelope struct {
+	Reply TrackReply `xml:"Body>TrackReply"`
 }
 
-// ActualDelivery returns the first ACTUAL_DELIVERY timestamp
-func (tr *TrackReply) ActualDelivery() *time.Time {
-	return tr.searchDatesOrTimes("ACTUAL_DELIVERY")
+func (t *TrackManyResponseEnvelope) Error() error {
+	err := t.Reply.replyError("Track")
+	if err != nil {
+		return fmt.Errorf("track reply error: %w", err)
+	}
+	return nil
 }
 
-// EstimatedDelivery returns the first ESTIMATED_DELIVERY timestamp
-func (tr *TrackReply) EstimatedDelivery() *time.Time {
-	return tr.searchDatesOrTimes("ESTIMATED_DELIVERY")
+// Warnings only returns the warnings of the whole reply, so that strict
+// warning codes don't fail every tracking number for one of them
+func (t *TrackManyResponseEnvelope) Warnings() []Warning {
+	return t.Reply.Reply.Warnings()
 }
 
-// Ship returns the first SHIP timestamp
-func (tr *TrackReply) Ship() *time.Time {
-	pickupTime := tr.searchEvents("PU")
-	if pickupTime != nil {
-		return pickupTime
-	}
+func (t *TrackResponseEnvelope) Warnings() []Warning {
+	return t.Reply.Warnings()
+}
 
-	return tr.searchDatesOrTimes("SHIP")
+// TrackReply : Track reply root (`xml:"Body>TrackReply"`)
+type TrackReply struct {
+	Reply
+	CompletedTrackDetails []CompletedTrackDetail
 }
 
-func (tr *TrackReply) Events() []Event {
-	events := []Event{}
+// Warnings returns the WARNING and NOTE notifications of the reply, including
+// those on each of its track details
+func (tr *TrackReply) Warnings() []Warning {
+	notifications := append([]Notification{}, tr.Notifications...)
 	for _, completedTrackDetail := range tr.CompletedTrackDetails {
+		notifications = append(notifications, completedTrackDetail.Notifications...)
 		for _, trackDetail := range completedTrackDetail.TrackDetails {
-			events = append(events, trackDetail.Events...)
+			notifications = append(notifications, trackDetail.Notification)
 		}
 	}
-	return events
+	return warnings(notifications)
 }
 
-func (tr *TrackReply) searchDatesOrTimes(dateOrTimeType string) *time.Time {
-	for _, completedTrackDetail := range tr.CompletedTrackDetails {
+// CompletedTrackDetail returns the completed track detail of trackingNumber,
+// or nil if the reply doesn't have it
+func (tr *TrackReply) CompletedTrackDetail(trackingNumber string) *CompletedTrackDetail {
+	for idx, completedTrackDetail := range tr.CompletedTrackDetails {
 		for _, trackDetail := range completedTrackDetail.TrackDetails {
-			for _, dateOrTime := range trackDetail.DatesOrTimes {
-				if dateOrTime.Type == dateOrTimeType {
-					ts := time.Time(dateOrTime.DateOrTimestamp)
-					return &ts
-				}
+			if trackDetail.TrackingNumber == trackingNumber {
+				return &tr.CompletedTrackDetails[idx]
 			}
 		}
 	}
-
 	return nil
 }
 
-func (tr *TrackReply) searchEvents(eventType string) *time.Time {
+// TrackDetails returns the track details of every package in the reply, e.g.
+// all the packages matching a reference
+func (tr *TrackReply) TrackDetails() []TrackDetail {
+	trackDetails := []TrackDetail{}
 	for _, completedTrackDetail := range tr.CompletedTrackDetails {
-		for _, trackDetail := range completedTrackDetail.TrackDetails {
-			for _, event := range trackDetail.Events {
-				if event.EventType == eventType {
-					ts := time.Time(event.Timestamp)
-					return &ts
-				}
+		trackDetails = append(trackDetails, completedTrackDetail.TrackDetails...)
+	}
+	return trackDetails
+}
+
+// DuplicateWaybill reports whether FedEx matched the tracking number to
+// several shipments, as it reuses tracking numbers
+func (tr *TrackReply) DuplicateWaybill() bool {
+	for _, completedTrackDetail := range tr.CompletedTrackDetails {
+		if completedTrackDetail.DuplicateWaybill {
+			return true
+		}
+	}
+	return false
+}
+
+// Candidates returns the shipments a duplicate waybill tracking number
+// matched, or nil if it isn't one. Pick one by ship date or
+// TrackingNumberUniqueIdentifier, and track it again by its unique identifier
+// for its details.
+func (tr *TrackReply) Candidates() []TrackDetail {
+	if !tr.DuplicateWaybill() {
+		return nil
+	}
+	return tr.TrackDetails()
+}
+
+// CandidateShippedOn returns the candidate shipped on the same day as
+// shipDate, or nil if there is none
+func (tr *TrackReply) CandidateShippedOn(shipDate time.Time) *TrackDetail {
+	candidates := tr.Candidates()
+	for idx := range candidates {
+		ship := candidates[idx].Ship()
+		if ship == nil {
+			continue
+		}
+		year, month, day := ship.Date()
+		shipYear, shipMonth, shipDay := shipDate.In(ship.Location()).Date()
+		if year == shipYear && month == shipMonth && day == shipDay {
+			return &candidates[idx]
+		}
+	}
+	return nil
+}
+
+// PrimaryTrackDetail returns the track detail the helpers of the reply read:
+// the most recently shipped candidate of a duplicate waybill, or else the
+// first track detail. It's nil if the reply has no track detail.
+func (tr *TrackReply) PrimaryTrackDetail() *TrackDetail {
+	trackDetails := tr.TrackDetails()
+	if len(trackDetails) == 0 {
+		return nil
+	}
+
+	primary := 0
+	if tr.DuplicateWaybill() {
+		for idx := range trackDetails {
+			ship, primaryShip := trackDetails[idx].Ship(), trackDetails[primary].Ship()
+			if ship != nil && (primaryShip == nil || ship.After(*primaryShip)) {
+				primary = idx
 			}
 		}
 	}
+	return &trackDetails[primary]
+}
+
+// ActualDelivery returns the ACTUAL_DELIVERY timestamp of the primary track
+// detail
+func (tr *TrackReply) ActualDelivery() *time.Time {
+	if primary := tr.PrimaryTrackDetail(); primary != nil {
+		return primary.ActualDelivery()
+	}
+	return nil
+}
+
+// EstimatedDelivery returns the ESTIMATED_DELIVERY timestamp of the primary
+// track detail
+func (tr *TrackReply) EstimatedDelivery() *time.Time {
+	if primary := tr.PrimaryTrackDetail(); primary != nil {
+		return primary.EstimatedDelivery()
+	}
 	return nil
 }
+
+// Ship returns the time the package of the primary track detail was picked
+// up, or else its SHIP timestamp
+func (tr *TrackReply) Ship() *time.Time {
+	if primary := tr.PrimaryTrackDetail(); primary != nil {
+		return primary.Ship()
+	}
+	return nil
+}
+
+// Events returns the events of the primary track detail
+func (tr *TrackReply) Events() []Event {
+	events := []Event{}
+	if primary := tr.PrimaryTrackDetail(); primary != nil {
+		events = append(events, primary.Events...)
+	}
+	return events
+}
+
+// TrackReference selects the packages shipped with a reference, e.g. the
+// RMANumber or InvoiceNumber of a Shipment
+type TrackReference struct {
+	// Type is one of the PackageIdentifierType constants
+	Type  string
+	Value string
+	// CarrierCode only searches the packages of one carrier, when set
+	CarrierCode string
+
+	// ShipDateRangeBegin and ShipDateRangeEnd bound the ship dates of the
+	// packages, when set
+	ShipDateRangeBegin time.Time
+	ShipDateRangeEnd   time.Time
+
+	// FedEx needs either the account the packages were shipped with or
+	// their destination. The account of the API is used when neither is set.
+	ShipmentAccountNumber  string
+	DestinationPostalCode  string
+	DestinationCountryCode string
+}
+
+// PackageIdentifier returns the identifier to track r with. Customer
+// references are sanitized like they are when shipping, so they match.
+func (r TrackReference) PackageIdentifier() PackageIdentifier {
+	value := r.Value
+	if r.Type == PackageIdentifierTypeCustomerReference || r.Type == PackageIdentifierTypeRMA {
+		value = sanitizeReferenceForFedexAPI(value)
+	}
+	return PackageIdentifier{Type: r.Type, Value: value}
+}
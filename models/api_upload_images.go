@@ -14,7 +14,11 @@ type UploadImagesResponseEnvelope struct {
 }
 
 func (u *UploadImagesResponseEnvelope) Error() error {
-	return u.Reply.Error()
+	return u.Reply.replyError("UploadImages")
+}
+
+func (u *UploadImagesResponseEnvelope) Warnings() []Warning {
+	return u.Reply.Warnings()
 }
 
 // UploadImagesReply : UploadImages reply root (`xml:"Body>UploadImagesReply"`)
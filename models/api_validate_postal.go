@@ -0,0 +1,95 @@
+package models
+
+import "strings"
+
+type ValidatePostalBody struct {
+	ValidatePostalRequest ValidatePostalRequest `xml:"q0:ValidatePostalRequest"`
+}
+
+type ValidatePostalRequest struct {
+	Request
+	ShipDateTime     Timestamp `xml:"q0:ShipDateTime"`
+	Address          Address   `xml:"q0:Address"`
+	CarrierCode      string    `xml:"q0:CarrierCode"`
+	CheckForMismatch bool      `xml:"q0:CheckForMismatch"`
+}
+
+type ValidatePostalResponseEnvelope struct {
+	Reply ValidatePostalReply `xml:"Body>ValidatePostalReply"`
+}
+
+func (v *ValidatePostalResponseEnvelope) Error() error {
+	return v.Reply.replyError("ValidatePostal")
+}
+
+func (v *ValidatePostalResponseEnvelope) Warnings() []Warning {
+	return v.Reply.Warnings()
+}
+
+// ValidatePostalReply : ValidatePostal reply root (`xml:"Body>ValidatePostalReply"`)
+type ValidatePostalReply struct {
+	Reply
+	PostalDetail PostalDetail
+}
+
+type PostalDetail struct {
+	StateOrProvinceCode string
+	// CityFirstInitials are the first letters of the cities of the postal
+	// code, FedEx doesn't return whole city names
+	CityFirstInitials    string
+	CleanedPostalCode    string
+	CountryCode          string
+	LocationDescriptions []PostalLocationDescription
+}
+
+// PostalLocationDescription is a FedEx station serving the postal code
+type PostalLocationDescription struct {
+	LocationID     string `xml:"LocationId"`
+	LocationNumber int
+	// ServiceArea is the express service area code of the postal code, like
+	// A1 or AM
+	ServiceArea string
+	AirportID   string `xml:"AirportId"`
+}
+
+// PostalValidation is a postal code validated by FedEx, normalized from a
+// ValidatePostalReply
+type PostalValidation struct {
+	PostalCode          string
+	StateOrProvinceCode string
+	CountryCode         string
+	// City is the city of the validated address when FedEx's city initials
+	// match it, or else the initials
+	City string
+	// Serviceable is true when a FedEx station serves the postal code, and
+	// ExpressServiceable when it has an express service area
+	Serviceable        bool
+	ExpressServiceable bool
+}
+
+// Validation returns the normalized validation of the postal code of address
+func (v *ValidatePostalReply) Validation(address Address) PostalValidation {
+	detail := v.PostalDetail
+	validation := PostalValidation{
+		PostalCode:          detail.CleanedPostalCode,
+		StateOrProvinceCode: detail.StateOrProvinceCode,
+		CountryCode:         detail.CountryCode,
+		City:                detail.CityFirstInitials,
+		Serviceable:         len(detail.LocationDescriptions) > 0,
+	}
+	if validation.CountryCode == "" {
+		validation.CountryCode = address.NormalizedCountryCode()
+	}
+
+	city := strings.ToUpper(strings.TrimSpace(address.City))
+	if city != "" && detail.CityFirstInitials != "" && strings.HasPrefix(city, strings.ToUpper(detail.CityFirstInitials)) {
+		validation.City = city
+	}
+
+	for _, location := range detail.LocationDescriptions {
+		if location.ServiceArea != "" {
+			validation.ExpressServiceable = true
+		}
+	}
+	return validation
+}
@@ -1,13 +1,31 @@
 package models
 
 const (
+	AddressAttributeResolved = "Resolved"
+
+	AddressClassificationBusiness    = "BUSINESS"
+	AddressClassificationMixed       = "MIXED"
+	AddressClassificationResidential = "RESIDENTIAL"
+	AddressClassificationUnknown     = "UNKNOWN"
+
+	AddressValidationStateNormalized   = "NORMALIZED"
+	AddressValidationStateRaw          = "RAW"
+	AddressValidationStateStandardized = "STANDARDIZED"
+
 	AggregationTypePerShipment              = "PER_SHIPMENT"
 	AncillaryEndorsementAddressCorrection   = "ADDRESS_CORRECTION"
 	BrokerTypeImport                        = "IMPORT"
 	BuildingPartSuite                       = "SUITE"
 	CarrierCodeFDXG                         = "FDXG"
+	CarrierCodeFXSP                         = "FXSP"
 	CommercialInvoicePurposeRepairAndReturn = "REPAIR_AND_RETURN"
 
+	CloseDocumentTypeCODReport                = "COD_REPORT"
+	CloseDocumentTypeDetailedDeliveryManifest = "DETAILED_DELIVERY_MANIFEST"
+	CloseDocumentTypeManifest                 = "MANIFEST"
+	CloseDocumentTypeMultiweightReport        = "MULTIWEIGHT_REPORT"
+	CloseDocumentTypeOP950                    = "OP_950" // hazardous materials certificate
+
 	CustomerImageUsageTypeLetterHead = "LETTER_HEAD"
 	CustomerImageUsageTypeSignature  = "SIGNATURE"
 
@@ -16,11 +34,18 @@ const (
 	CustomerReferenceTypeInvoice           = "INVOICE_NUMBER"
 	CustomerReferenceValueNaftaCoo         = "NAFTA_COO"
 
+	DeletionControlDeleteAllPackages = "DELETE_ALL_PACKAGES"
+	DeletionControlDeleteOnePackage  = "DELETE_ONE_PACKAGE"
+
 	DimensionsUnitsIn = "IN"
 	DimensionsUnitsCm = "CM"
 
-	DropoffTypeRegularPickup      = "REGULAR_PICKUP"
-	DocumentTypeCommercialInvoice = "COMMERCIAL_INVOICE"
+	DistanceUnitsKM = "KM"
+	DistanceUnitsMI = "MI"
+
+	DropoffTypeRegularPickup             = "REGULAR_PICKUP"
+	DocumentTypeCommercialInvoice        = "COMMERCIAL_INVOICE"
+	DocumentTypeSignatureProofOfDelivery = "SIGNATURE_PROOF_OF_DELIVERY"
 
 	ImageTypePDF = "PDF"
 	ImageTypePNG = "PNG"
@@ -31,9 +56,30 @@ const (
 	PackagingBag               = "BAG"
 	PackagingTypeYourPackaging = "YOUR_PACKAGING"
 
+	LocationsSearchCriterionAddress               = "ADDRESS"
+	LocationsSearchCriterionGeographicCoordinates = "GEOGRAPHIC_COORDINATES"
+
+	LocationTypeFedexAuthorizedShipCenter = "FEDEX_AUTHORIZED_SHIP_CENTER"
+	LocationTypeFedexExpressStation       = "FEDEX_EXPRESS_STATION"
+	LocationTypeFedexOffice               = "FEDEX_OFFICE"
+	LocationTypeFedexOnsite               = "FEDEX_ONSITE"
+	LocationTypeFedexSelfServiceLocation  = "FEDEX_SELF_SERVICE_LOCATION"
+	LocationTypeFedexShipAndGet           = "FEDEX_SHIP_AND_GET"
+	LocationTypeFedexShipsite             = "FEDEX_SHIPSITE"
+
+	PackageIdentifierTypeCustomerReference           = "CUSTOMER_REFERENCE"
+	PackageIdentifierTypeInvoice                     = "INVOICE"
+	PackageIdentifierTypePurchaseOrder               = "PURCHASE_ORDER"
+	PackageIdentifierTypeRMA                         = "RMA"
+	PackageIdentifierTypeTrackingNumber              = "TRACKING_NUMBER_OR_DOORTAG"
+	PackageIdentifierTypeTransportationControlNumber = "TRANSPORTATION_CONTROL_NUMBER"
+
 	PaymentTypeRecipient = "RECIPIENT"
 	PaymentTypeSender    = "SENDER"
 
+	PickupRequestTypeFutureDay = "FUTURE_DAY"
+	PickupRequestTypeSameDay   = "SAME_DAY"
+
 	PreferredCurrencyUSD = "USD"
 
 	RateTypePreferredAccountPackage = "PREFERRED_ACCOUNT_PACKAGE"
@@ -53,4 +99,8 @@ const (
 	StockTypePaperLetter = "PAPER_LETTER"
 	StockTypePaper4x6    = "PAPER_4X6"
 	WeightUnitsLB        = "LB"
+
+	TrackingIDTypeFedex = "FEDEX"
+
+	TransferOfPossessionTypeDropoff = "DROPOFF"
 )
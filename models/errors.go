@@ -1,7 +1,106 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors for common FedEx failures. Errors returned by the api
+// package wrap a *ReplyError, so these can be checked with errors.Is.
+var (
+	ErrTrackingNotFound        = errors.New("tracking number not found")
+	ErrPickupAlreadyExists     = error(PickupAlreadyExistsError{})
+	ErrShipmentAlreadyTendered = error(ShipmentAlreadyTenderedError{})
+	ErrInvalidAddress          = errors.New("invalid address")
+	ErrAuthFailure             = errors.New("authentication failed")
+	ErrServiceUnavailable      = errors.New("service unavailable")
+	ErrNothingToClose          = errors.New("nothing to close")
+)
+
+// notificationCodeErrors maps FedEx notification codes to sentinel errors
+var notificationCodeErrors = map[string]error{
+	"1000": ErrAuthFailure,
+	"9040": ErrTrackingNotFound,
+}
+
+// notificationMessageErrors maps fragments of lowercased notification
+// messages to sentinel errors, for failures FedEx doesn't give a stable code
+var notificationMessageErrors = []struct {
+	fragment string
+	err      error
+}{
+	{"tracking number cannot be found", ErrTrackingNotFound},
+	{"pickup already exists", ErrPickupAlreadyExists},
+	{"already been tendered", ErrShipmentAlreadyTendered},
+	{"already tendered", ErrShipmentAlreadyTendered},
+	{"invalid address", ErrInvalidAddress},
+	{"invalid postal code", ErrInvalidAddress},
+	{"postal code or routing code is required", ErrInvalidAddress},
+	{"address line 1 is required", ErrInvalidAddress},
+	{"authentication failed", ErrAuthFailure},
+	{"service unavailable", ErrServiceUnavailable},
+	{"temporarily unavailable", ErrServiceUnavailable},
+	{"no shipments to close", ErrNothingToClose},
+	{"no shipments found to close", ErrNothingToClose},
+}
+
 type PickupAlreadyExistsError struct{}
 
 func (p PickupAlreadyExistsError) Error() string {
 	return "pickup already exists"
 }
+
+// ShipmentAlreadyTenderedError is returned when deleting a shipment FedEx
+// already has, which can't be deleted anymore
+type ShipmentAlreadyTenderedError struct{}
+
+func (s ShipmentAlreadyTenderedError) Error() string {
+	return "shipment already tendered"
+}
+
+// ReplyError is a failed reply from FedEx. It carries every notification of
+// the reply, not just the one used for the error message.
+type ReplyError struct {
+	Operation string
+	// TransactionID is the CustomerTransactionId of the request, which FedEx
+	// echoes back. It's empty for requests without one, which is all of them
+	// but Rate. FedEx replies don't have a transaction ID of their own.
+	TransactionID   string
+	HighestSeverity string
+	Notifications   []Notification
+}
+
+func (e *ReplyError) Error() string {
+	for _, notification := range e.Notifications {
+		if notification.Severity == e.HighestSeverity {
+			return fmt.Sprintf("reply got error: %s", notification.Message)
+		}
+	}
+	return fmt.Sprintf("reply got status: %s", e.HighestSeverity)
+}
+
+// Is reports whether a notification of the highest severity matches target,
+// which should be one of the sentinel errors above
+func (e *ReplyError) Is(target error) bool {
+	for _, notification := range e.Notifications {
+		if notification.Severity == e.HighestSeverity && notification.matches(target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n Notification) matches(target error) bool {
+	if err, ok := notificationCodeErrors[n.Code]; ok && err == target {
+		return true
+	}
+
+	message := strings.ToLower(n.Message)
+	for _, messageError := range notificationMessageErrors {
+		if messageError.err == target && strings.Contains(message, messageError.fragment) {
+			return true
+		}
+	}
+	return false
+}
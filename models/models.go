@@ -1,8 +1,10 @@
 package models
 
 import (
+	"encoding/xml"
 	"fmt"
 	"math"
+	"time"
 )
 
 type Address struct {
@@ -40,6 +42,17 @@ type AddressReply struct {
 	CountryName         string   `xml:"CountryName"`
 }
 
+// Address returns a as an Address
+func (a AddressReply) Address() Address {
+	return Address{
+		StreetLines:         a.StreetLines,
+		City:                a.City,
+		StateOrProvinceCode: a.StateOrProvinceCode,
+		PostalCode:          a.PostalCode,
+		CountryCode:         a.CountryCode,
+	}
+}
+
 type AdvanceNotificationDetail struct {
 	EstimatedTimeOfArrival Timestamp
 	Reason                 string
@@ -170,8 +183,30 @@ type CompletedTrackDetail struct {
 	HighestSeverity  string
 	Notifications    []Notification
 	DuplicateWaybill bool
-	MoreData         bool
-	TrackDetails     []TrackDetail
+	// MoreData is set when FedEx has more track details than in the reply,
+	// on the page of PagingToken
+	MoreData          bool
+	PagingToken       string
+	TrackDetailsCount int
+	TrackDetails      []TrackDetail
+}
+
+// Error returns a *ReplyError if FedEx couldn't track the package, e.g. when
+// the tracking number isn't found, or nil
+func (c CompletedTrackDetail) Error() error {
+	if c.HighestSeverity == notificationSeverityError || c.HighestSeverity == notificationSeverityFailure {
+		return &ReplyError{
+			Operation:       "Track",
+			HighestSeverity: c.HighestSeverity,
+			Notifications:   c.Notifications,
+		}
+	}
+	for _, trackDetail := range c.TrackDetails {
+		if err := trackDetail.replyError(""); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type CommercialInvoice struct {
@@ -191,6 +226,24 @@ type Contact struct {
 	EmailAddress string `xml:"q0:EMailAddress"`
 }
 
+// ContactReply is a Contact in a reply, see AddressReply
+type ContactReply struct {
+	PersonName   string
+	CompanyName  string
+	PhoneNumber  string
+	EmailAddress string `xml:"EMailAddress"`
+}
+
+// Contact returns c as a Contact
+func (c ContactReply) Contact() Contact {
+	return Contact{
+		PersonName:   c.PersonName,
+		CompanyName:  c.CompanyName,
+		PhoneNumber:  c.PhoneNumber,
+		EmailAddress: c.EmailAddress,
+	}
+}
+
 type ContactAndAddress struct {
 	Contact Contact `xml:"q0:Contact"`
 	Address Address `xml:"q0:Address"`
@@ -336,17 +389,7 @@ type FromAndTo struct {
 }
 
 func (ft FromAndTo) IsInternational() bool {
-	fromCountryCode := ft.FromAddress.CountryCode
-	if fromCountryCode == "" {
-		fromCountryCode = "US"
-	}
-
-	toCountryCode := ft.ToAddress.CountryCode
-	if toCountryCode == "" {
-		toCountryCode = "US"
-	}
-
-	return fromCountryCode != toCountryCode
+	return ft.FromAddress.NormalizedCountryCode() != ft.ToAddress.NormalizedCountryCode()
 }
 
 type Identifier struct {
@@ -464,16 +507,31 @@ type RateDetail struct {
 }
 
 type RateReplyDetail struct {
-	ServiceType                     string
-	ServiceDescription              ServiceDescription
-	PackagingType                   string
+	ServiceType        string
+	ServiceDescription ServiceDescription
+	PackagingType      string
+	// DeliveryTimestamp, CommitDetails and the transit times are only set
+	// when requested with ReturnTransitAndCommit
+	DeliveryDayOfWeek               string
+	DeliveryTimestamp               Timestamp
+	CommitDetails                   []CommitDetail
 	DestinationAirportID            string `xml:"DestinationAirportId"`
 	IneligibleForMoneyBackGuarantee bool
+	TransitTime                     string
+	MaximumTransitTime              string
 	SignatureOption                 string
 	ActualRateType                  string
 	RatedShipmentDetails            []Rating
 }
 
+type CommitDetail struct {
+	ServiceType        string
+	CommitTimestamp    Timestamp
+	DayOfWeek          string
+	TransitTime        string
+	MaximumTransitTime string
+}
+
 type RatedPackage struct {
 	GroupNumber          string
 	EffectiveNetDiscount Charge
@@ -510,6 +568,7 @@ type Reconciliation struct {
 type RequestedPackageLineItem struct {
 	SequenceNumber     int                 `xml:"q0:SequenceNumber"`
 	GroupPackageCount  int                 `xml:"q0:GroupPackageCount,omitempty"`
+	InsuredValue       *Money              `xml:"q0:InsuredValue,omitempty"`
 	Weight             Weight              `xml:"q0:Weight"`
 	Dimensions         Dimensions          `xml:"q0:Dimensions"`
 	PhysicalPackaging  string              `xml:"q0:PhysicalPackaging"`
@@ -541,6 +600,7 @@ type RequestedShipment struct {
 	ShippingDocumentSpecification *ShippingDocumentSpecification `xml:"q0:ShippingDocumentSpecification"`
 	RateRequestTypes              *string                        `xml:"q0:RateRequestTypes"`
 	EdtRequestType                *string                        `xml:"q0:EdtRequestType"`
+	MasterTrackingID              *RequestedTrackingID           `xml:"q0:MasterTrackingId,omitempty"`
 	PackageCount                  *int                           `xml:"q0:PackageCount"`
 	RequestedPackageLineItems     []RequestedPackageLineItem     `xml:"q0:RequestedPackageLineItems"`
 }
@@ -558,16 +618,43 @@ type Package struct {
 	RecipientDetails                []RecipientDetail
 }
 
+// PackageDetail is one package of a Rate or Shipment with several packages
+type PackageDetail struct {
+	// Weight and Dimensions default to the ones of a single package when zero
+	Weight     Weight
+	Dimensions Dimensions
+	// References are added to the customer references of the shipment
+	References    []string
+	DeclaredValue *Money
+}
+
 type PickupLocation struct {
 	Contact Contact `xml:"q0:Contact"`
 	Address Address `xml:"q0:Address"`
 }
 
 type SelectionDetails struct {
-	CarrierCode       string            `xml:"q0:CarrierCode"`
-	PackageIdentifier PackageIdentifier `xml:"q0:PackageIdentifier"`
-	// Destination           Destination
-	// ShipmentAccountNumber string
+	CarrierCode                    string                `xml:"q0:CarrierCode,omitempty"`
+	PackageIdentifier              PackageIdentifier     `xml:"q0:PackageIdentifier"`
+	TrackingNumberUniqueIdentifier string                `xml:"q0:TrackingNumberUniqueIdentifier,omitempty"`
+	ShipDateRangeBegin             string                `xml:"q0:ShipDateRangeBegin,omitempty"`
+	ShipDateRangeEnd               string                `xml:"q0:ShipDateRangeEnd,omitempty"`
+	ShipmentAccountNumber          string                `xml:"q0:ShipmentAccountNumber,omitempty"`
+	Destination                    *SelectionDestination `xml:"q0:Destination,omitempty"`
+	PagingDetail                   *PagingDetail         `xml:"q0:PagingDetail,omitempty"`
+}
+
+// PagingDetail requests the page of a previous reply's PagingToken
+type PagingDetail struct {
+	PagingToken            string `xml:"q0:PagingToken,omitempty"`
+	NumberOfResultsPerPage int    `xml:"q0:NumberOfResultsPerPage,omitempty"`
+}
+
+// SelectionDestination narrows a track by reference to packages shipped to a
+// postal code
+type SelectionDestination struct {
+	PostalCode  string `xml:"q0:PostalCode,omitempty"`
+	CountryCode string `xml:"q0:CountryCode"`
 }
 
 type Service struct {
@@ -717,15 +804,100 @@ type TrackDetail struct {
 	Events                                 []Event
 }
 
+// ActualDelivery returns the ACTUAL_DELIVERY timestamp
+func (t TrackDetail) ActualDelivery() *time.Time {
+	return t.searchDatesOrTimes("ACTUAL_DELIVERY")
+}
+
+// EstimatedDelivery returns the ESTIMATED_DELIVERY timestamp
+func (t TrackDetail) EstimatedDelivery() *time.Time {
+	return t.searchDatesOrTimes("ESTIMATED_DELIVERY")
+}
+
+// Ship returns the time the package was picked up, or else the SHIP timestamp.
+// The oldest picked up event is the pickup, later ones like IP are hub scans.
+func (t TrackDetail) Ship() *time.Time {
+	for _, event := range t.Timeline() {
+		if event.Status() == TrackingStatusPickedUp {
+			ts := time.Time(event.Timestamp)
+			return &ts
+		}
+	}
+	return t.searchDatesOrTimes("SHIP")
+}
+
+func (t TrackDetail) searchDatesOrTimes(dateOrTimeType string) *time.Time {
+	for _, dateOrTime := range t.DatesOrTimes {
+		if dateOrTime.Type == dateOrTimeType {
+			ts := time.Time(dateOrTime.DateOrTimestamp)
+			return &ts
+		}
+	}
+	return nil
+}
+
+// Error returns a *ReplyError if the notification of the track detail is an
+// error, or nil
+func (t TrackDetail) Error() error {
+	return t.replyError("")
+}
+
+func (t TrackDetail) replyError(transactionID string) error {
+	if t.Notification.Severity != notificationSeverityError && t.Notification.Severity != notificationSeverityFailure {
+		return nil
+	}
+	return &ReplyError{
+		Operation:       "Track",
+		TransactionID:   transactionID,
+		HighestSeverity: t.Notification.Severity,
+		Notifications:   []Notification{t.Notification},
+	}
+}
+
 type TrackingID struct {
 	TrackingIdType string
+	FormId         string
 	TrackingNumber string
 }
 
+// Requested returns t as sent in requests
+func (t TrackingID) Requested() *RequestedTrackingID {
+	return &RequestedTrackingID{
+		TrackingIDType: t.TrackingIdType,
+		FormID:         t.FormId,
+		TrackingNumber: t.TrackingNumber,
+	}
+}
+
+// RequestedTrackingID is a TrackingID sent in requests, like the master
+// tracking ID of a multi-piece shipment
+type RequestedTrackingID struct {
+	TrackingIDType string `xml:"q0:TrackingIdType,omitempty"`
+	FormID         string `xml:"q0:FormId,omitempty"`
+	TrackingNumber string `xml:"q0:TrackingNumber"`
+}
+
+// MasterTrackingID is the TrackingID of the master package sent with the
+// other packages of a multi-piece shipment
+type MasterTrackingID = RequestedTrackingID
+
 type TransactionDetail struct {
 	CustomerTransactionID string `xml:"q0:CustomerTransactionId,omitempty"`
 }
 
+// UnmarshalXML reads the TransactionDetail FedEx echoes back in replies, which
+// isn't prefixed like the one we send
+func (t *TransactionDetail) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var reply struct {
+		CustomerTransactionID string `xml:"CustomerTransactionId"`
+	}
+	if err := d.DecodeElement(&reply, &start); err != nil {
+		return err
+	}
+	t.CustomerTransactionID = reply.CustomerTransactionID
+	return nil
+}
+
 type Weight struct {
 	Units string  `xml:"q0:Units"`
 	Value float64 `xml:"q0:Value"`
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RateQuote is the rate of one service, normalized from a RateReplyDetail
+type RateQuote struct {
+	ServiceType        string
+	ServiceDescription string
+	TotalNetCharge     Charge
+	// TransitDays and MaximumTransitDays are the business days in transit,
+	// zero when FedEx doesn't say
+	TransitDays        int
+	MaximumTransitDays int
+	// Delivery is when FedEx commits to deliver, or else the ship time plus
+	// the business days in transit. It's nil when neither is known.
+	Delivery *time.Time
+	// MoneyBackGuarantee says whether FedEx refunds late deliveries
+	MoneyBackGuarantee bool
+}
+
+// RateQuotes are the quotes of several services
+type RateQuotes []RateQuote
+
+// transitDays are the days of the FedEx TransitTime values
+var transitDays = map[string]int{}
+
+func init() {
+	numbers := []string{
+		"ONE", "TWO", "THREE", "FOUR", "FIVE", "SIX", "SEVEN", "EIGHT", "NINE", "TEN",
+		"ELEVEN", "TWELVE", "THIRTEEN", "FOURTEEN", "FIFTEEN", "SIXTEEN", "SEVENTEEN",
+		"EIGHTEEN", "NINETEEN", "TWENTY",
+	}
+	for idx, number := range numbers {
+		suffix := "_DAYS"
+		if idx == 0 {
+			suffix = "_DAY"
+		}
+		transitDays[number+suffix] = idx + 1
+	}
+}
+
+// Quotes returns a quote per service of the reply, for packages shipped at
+// shipTime. Services without a rate are skipped.
+func (rr *RateReply) Quotes(shipTime time.Time) RateQuotes {
+	quotes := RateQuotes{}
+	for _, rateReplyDetail := range rr.RateReplyDetails {
+		rateDetail, err := preferredRateDetail(rateReplyDetail.RatedShipmentDetails)
+		if err != nil {
+			continue
+		}
+
+		quote := RateQuote{
+			ServiceType:        rateReplyDetail.ServiceType,
+			ServiceDescription: rateReplyDetail.ServiceDescription.Description,
+			TotalNetCharge:     rateDetail.TotalNetCharge,
+			TransitDays:        transitDays[rateReplyDetail.TransitTime],
+			MaximumTransitDays: transitDays[rateReplyDetail.MaximumTransitTime],
+			MoneyBackGuarantee: !rateReplyDetail.IneligibleForMoneyBackGuarantee,
+		}
+
+		delivery := time.Time(rateReplyDetail.DeliveryTimestamp)
+		for _, commitDetail := range rateReplyDetail.CommitDetails {
+			if delivery.IsZero() {
+				delivery = time.Time(commitDetail.CommitTimestamp)
+			}
+			if quote.TransitDays == 0 {
+				quote.TransitDays = transitDays[commitDetail.TransitTime]
+			}
+			if quote.MaximumTransitDays == 0 {
+				quote.MaximumTransitDays = transitDays[commitDetail.MaximumTransitTime]
+			}
+		}
+		if delivery.IsZero() && quote.latestTransitDays() > 0 {
+			delivery = addBusinessDays(shipTime, quote.latestTransitDays())
+		}
+		if !delivery.IsZero() {
+			quote.Delivery = &delivery
+		}
+
+		quotes = append(quotes, quote)
+	}
+	return quotes
+}
+
+// latestTransitDays returns the most business days the package may be in
+// transit
+func (q RateQuote) latestTransitDays() int {
+	if q.MaximumTransitDays > q.TransitDays {
+		return q.MaximumTransitDays
+	}
+	return q.TransitDays
+}
+
+func addBusinessDays(t time.Time, days int) time.Time {
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return t
+}
+
+// ErrMixedCurrencies is returned when comparing the charges of quotes in
+// different currencies
+var ErrMixedCurrencies = errors.New("quotes in different currencies")
+
+// checkCurrencies returns ErrMixedCurrencies if the quotes aren't all charged
+// in the same currency
+func (q RateQuotes) checkCurrencies() error {
+	for idx := range q {
+		if currency := q[idx].TotalNetCharge.Currency; currency != q[0].TotalNetCharge.Currency {
+			return fmt.Errorf("%w: %s and %s", ErrMixedCurrencies, q[0].TotalNetCharge.Currency, currency)
+		}
+	}
+	return nil
+}
+
+// Cheapest returns the quote with the lowest charge, or nil if there are
+// none. It fails with ErrMixedCurrencies when the quotes are in different
+// currencies.
+func (q RateQuotes) Cheapest() (*RateQuote, error) {
+	if err := q.checkCurrencies(); err != nil {
+		return nil, err
+	}
+
+	var cheapest *RateQuote
+	for idx := range q {
+		if cheapest == nil || q[idx].TotalNetCharge.Amount < cheapest.TotalNetCharge.Amount {
+			cheapest = &q[idx]
+		}
+	}
+	return cheapest, nil
+}
+
+// Fastest returns the quote delivered the soonest, the cheapest of them if
+// several are, or nil if no quote has a delivery. It fails with
+// ErrMixedCurrencies when the quotes are in different currencies.
+func (q RateQuotes) Fastest() (*RateQuote, error) {
+	if err := q.checkCurrencies(); err != nil {
+		return nil, err
+	}
+
+	var fastest *RateQuote
+	for idx := range q {
+		quote := &q[idx]
+		if quote.Delivery == nil {
+			continue
+		}
+		if fastest == nil || quote.Delivery.Before(*fastest.Delivery) ||
+			(quote.Delivery.Equal(*fastest.Delivery) && quote.TotalNetCharge.Amount < fastest.TotalNetCharge.Amount) {
+			fastest = quote
+		}
+	}
+	return fastest, nil
+}
+
+// CheapestArrivingBy returns the cheapest quote delivered by deadline, or nil
+// if there is none. It fails with ErrMixedCurrencies when the quotes are in
+// different currencies.
+func (q RateQuotes) CheapestArrivingBy(deadline time.Time) (*RateQuote, error) {
+	if err := q.checkCurrencies(); err != nil {
+		return nil, err
+	}
+
+	var cheapest *RateQuote
+	for idx := range q {
+		quote := &q[idx]
+		if quote.Delivery == nil || quote.Delivery.After(deadline) {
+			continue
+		}
+		if cheapest == nil || quote.TotalNetCharge.Amount < cheapest.TotalNetCharge.Amount {
+			cheapest = quote
+		}
+	}
+	return cheapest, nil
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// TrackingStatus is a FedEx tracking status normalized from the status and
+// event codes FedEx uses
+type TrackingStatus string
+
+// Normalized tracking statuses
+const (
+	TrackingStatusUnknown           TrackingStatus = "UNKNOWN"
+	TrackingStatusLabelCreated      TrackingStatus = "LABEL_CREATED"
+	TrackingStatusPickedUp          TrackingStatus = "PICKED_UP"
+	TrackingStatusInTransit         TrackingStatus = "IN_TRANSIT"
+	TrackingStatusOutForDelivery    TrackingStatus = "OUT_FOR_DELIVERY"
+	TrackingStatusDelivered         TrackingStatus = "DELIVERED"
+	TrackingStatusException         TrackingStatus = "EXCEPTION"
+	TrackingStatusReturnedToShipper TrackingStatus = "RETURNED_TO_SHIPPER"
+	TrackingStatusCancelled         TrackingStatus = "CANCELLED"
+)
+
+// trackingStatuses maps the FedEx status and event codes to normalized
+// statuses. Codes not listed here are TrackingStatusUnknown.
+//
+//	Code  FedEx description                  Status
+//	OC    Shipment information sent to FedEx LABEL_CREATED
+//	DS    Vehicle dispatched                 LABEL_CREATED
+//	EP    Enroute to pickup                  LABEL_CREATED
+//	PU    Picked up                          PICKED_UP
+//	PX    Picked up (see details)            PICKED_UP
+//	IP    In FedEx possession                PICKED_UP
+//	AA    At airport                         IN_TRANSIT
+//	AC    At Canada Post facility            IN_TRANSIT
+//	AF    At local FedEx facility            IN_TRANSIT
+//	AR    Arrived at FedEx location          IN_TRANSIT
+//	CC    Cleared customs                    IN_TRANSIT
+//	CH    Location changed                   IN_TRANSIT
+//	CP    Clearance in progress              IN_TRANSIT
+//	DP    Departed FedEx location            IN_TRANSIT
+//	EA    Enroute to airport                 IN_TRANSIT
+//	EO    Enroute to origin airport          IN_TRANSIT
+//	FD    At FedEx destination               IN_TRANSIT
+//	HL    Hold at location                   IN_TRANSIT
+//	IT    In transit                         IN_TRANSIT
+//	LO    Left origin                        IN_TRANSIT
+//	OF    At FedEx origin facility           IN_TRANSIT
+//	PF    Plane in flight                    IN_TRANSIT
+//	PL    Plane landed                       IN_TRANSIT
+//	PM    In progress                        IN_TRANSIT
+//	SP    Split status                       IN_TRANSIT
+//	TR    Transfer                           IN_TRANSIT
+//	AD    At delivery                        OUT_FOR_DELIVERY
+//	OD    On FedEx vehicle for delivery      OUT_FOR_DELIVERY
+//	DL    Delivered                          DELIVERED
+//	CD    Clearance delay                    EXCEPTION
+//	DD    Delivery delay                     EXCEPTION
+//	DE    Delivery exception                 EXCEPTION
+//	DY    Delay                              EXCEPTION
+//	PD    Pickup delay                       EXCEPTION
+//	SE    Shipment exception                 EXCEPTION
+//	RS    Return to shipper                  RETURNED_TO_SHIPPER
+//	CA    Shipment cancelled                 CANCELLED
+var trackingStatuses = map[string]TrackingStatus{
+	"OC": TrackingStatusLabelCreated,
+	"DS": TrackingStatusLabelCreated,
+	"EP": TrackingStatusLabelCreated,
+	"PU": TrackingStatusPickedUp,
+	"PX": TrackingStatusPickedUp,
+	"IP": TrackingStatusPickedUp,
+	"AA": TrackingStatusInTransit,
+	"AC": TrackingStatusInTransit,
+	"AF": TrackingStatusInTransit,
+	"AR": TrackingStatusInTransit,
+	"CC": TrackingStatusInTransit,
+	"CH": TrackingStatusInTransit,
+	"CP": TrackingStatusInTransit,
+	"DP": TrackingStatusInTransit,
+	"EA": TrackingStatusInTransit,
+	"EO": TrackingStatusInTransit,
+	"FD": TrackingStatusInTransit,
+	"HL": TrackingStatusInTransit,
+	"IT": TrackingStatusInTransit,
+	"LO": TrackingStatusInTransit,
+	"OF": TrackingStatusInTransit,
+	"PF": TrackingStatusInTransit,
+	"PL": TrackingStatusInTransit,
+	"PM": TrackingStatusInTransit,
+	"SP": TrackingStatusInTransit,
+	"TR": TrackingStatusInTransit,
+	"AD": TrackingStatusOutForDelivery,
+	"OD": TrackingStatusOutForDelivery,
+	"DL": TrackingStatusDelivered,
+	"CD": TrackingStatusException,
+	"DD": TrackingStatusException,
+	"DE": TrackingStatusException,
+	"DY": TrackingStatusException,
+	"PD": TrackingStatusException,
+	"SE": TrackingStatusException,
+	"RS": TrackingStatusReturnedToShipper,
+	"CA": TrackingStatusCancelled,
+}
+
+// TrackingStatusOf returns the normalized status of a FedEx status or event
+// code
+func TrackingStatusOf(code string) TrackingStatus {
+	status, ok := trackingStatuses[code]
+	if !ok {
+		return TrackingStatusUnknown
+	}
+	return status
+}
+
+// Status returns the normalized status of the event
+func (e Event) Status() TrackingStatus {
+	return TrackingStatusOf(e.EventType)
+}
+
+// Status returns the current normalized status of the package, from its
+// status detail or else its latest event. A package delivered after being
+// sent back is TrackingStatusReturnedToShipper.
+func (t TrackDetail) Status() TrackingStatus {
+	status := TrackingStatusOf(t.StatusDetail.Code)
+	timeline := t.Timeline()
+	if status == TrackingStatusUnknown && len(timeline) > 0 {
+		status = timeline[len(timeline)-1].Status()
+	}
+
+	if status == TrackingStatusDelivered {
+		for _, event := range timeline {
+			if event.Status() == TrackingStatusReturnedToShipper {
+				return TrackingStatusReturnedToShipper
+			}
+		}
+	}
+	return status
+}
+
+// Timeline returns the events of the package, oldest first. FedEx returns
+// them most recent first.
+func (t TrackDetail) Timeline() []Event {
+	// Reverse first, so events with the same timestamp stay in order
+	timeline := make([]Event, len(t.Events))
+	for idx, event := range t.Events {
+		timeline[len(t.Events)-1-idx] = event
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return time.Time(timeline[i].Timestamp).Before(time.Time(timeline[j].Timestamp))
+	})
+	return timeline
+}
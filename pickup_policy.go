@@ -0,0 +1,146 @@
+package fedex
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// PickupHours are when packages are ready for pickup and the location
+// closes, as times since midnight local to the pickup
+type PickupHours struct {
+	Ready time.Duration
+	Close time.Duration
+}
+
+// PickupPolicy says when CreatePickup schedules pickups
+type PickupPolicy struct {
+	// Hours are the hours of every location, unless in LocationHours
+	Hours PickupHours
+	// LocationHours are the hours of the locations of some postal codes
+	LocationHours map[string]PickupHours
+	// BlockedWeekdays have no pickups
+	BlockedWeekdays []time.Weekday
+	// Holidays have no pickups. Only their year, month and day are used.
+	Holidays []time.Time
+	// Horizon is how many days after today pickups are tried on
+	Horizon int
+	// CheckAvailability only tries the days FedEx says are available, with
+	// GetPickupAvailability
+	CheckAvailability bool
+	// TimeZoneResolver resolves the time zone of pickup addresses.
+	// DefaultTimeZoneResolver is used when nil.
+	TimeZoneResolver TimeZoneResolver
+}
+
+// DefaultPickupPolicy is used when Fedex.PickupPolicy is nil. Pickups are
+// from 10:45 to 18:45, except on Sundays, within the next five days.
+var DefaultPickupPolicy = PickupPolicy{
+	Hours: PickupHours{
+		Ready: 10*time.Hour + 45*time.Minute,
+		Close: 18*time.Hour + 45*time.Minute,
+	},
+	BlockedWeekdays: []time.Weekday{time.Sunday},
+	Horizon:         5,
+}
+
+// Windows returns the pickup windows at address from now until the horizon,
+// in order. Today is skipped once past its ready time.
+func (p PickupPolicy) Windows(address models.Address, now time.Time) ([]models.PickupTimeWindow, error) {
+	resolver := p.TimeZoneResolver
+	if resolver == nil {
+		resolver = DefaultTimeZoneResolver
+	}
+	location, err := resolver.TimeZone(address)
+	if err != nil {
+		return nil, fmt.Errorf("resolve time zone: %w", err)
+	}
+
+	hours := p.Hours
+	if locationHours, ok := p.LocationHours[address.PostalCode]; ok {
+		hours = locationHours
+	}
+
+	windows := []models.PickupTimeWindow{}
+	now = now.In(location)
+	for delay := 0; delay <= p.Horizon; delay++ {
+		day := time.Date(now.Year(), now.Month(), now.Day()+delay, 0, 0, 0, 0, location)
+		if p.isBlocked(day) {
+			continue
+		}
+
+		readyTime := atTimeOfDay(day, hours.Ready)
+		if readyTime.Before(now) {
+			continue
+		}
+		windows = append(windows, models.PickupTimeWindow{
+			ReadyTime: readyTime,
+			CloseTime: atTimeOfDay(day, hours.Close),
+		})
+	}
+	return windows, nil
+}
+
+func (p PickupPolicy) isBlocked(day time.Time) bool {
+	for _, weekday := range p.BlockedWeekdays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	for _, holiday := range p.Holidays {
+		if holiday.Year() == day.Year() && holiday.Month() == day.Month() && holiday.Day() == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+// atTimeOfDay returns the wall clock timeOfDay of day. Adding timeOfDay to
+// midnight would be an hour off on days changing to or from DST.
+func atTimeOfDay(day time.Time, timeOfDay time.Duration) time.Time {
+	hours := int(timeOfDay / time.Hour)
+	minutes := int(timeOfDay % time.Hour / time.Minute)
+	seconds := int(timeOfDay % time.Minute / time.Second)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, seconds, 0, day.Location())
+}
+
+// businessDaysUntil returns the weekdays after the day of from, up to the day
+// of to
+func businessDaysUntil(from, to time.Time) int {
+	days := 0
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	for day = day.AddDate(0, 0, 1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
+
+// filterAvailableWindows returns the windows on days of options, which can
+// still be requested at now and leave FedEx the access time
+func filterAvailableWindows(windows []models.PickupTimeWindow, options []models.PickupScheduleOption, now time.Time) []models.PickupTimeWindow {
+	byDate := map[string]models.PickupScheduleOption{}
+	for _, option := range options {
+		byDate[option.PickupDate] = option
+	}
+
+	available := []models.PickupTimeWindow{}
+	for _, window := range windows {
+		option, ok := byDate[window.ReadyTime.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		if cutOff, err := option.CutOff(window.ReadyTime.Location()); err == nil && now.After(cutOff) {
+			continue
+		}
+		// FedEx needs the packages ready for at least the access time
+		if accessTime, err := option.AccessDuration(); err == nil && window.CloseTime.Sub(window.ReadyTime) < accessTime {
+			continue
+		}
+		available = append(available, window)
+	}
+	return available
+}
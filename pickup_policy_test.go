@@ -0,0 +1,94 @@
+package fedex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestPickupPolicy(t *testing.T) {
+	losAngeles, _ := time.LoadLocation("America/Los_Angeles")
+	policy := PickupPolicy{
+		Hours:           DefaultPickupPolicy.Hours,
+		LocationHours:   map[string]PickupHours{"90401": {Ready: 9 * time.Hour, Close: 17 * time.Hour}},
+		BlockedWeekdays: []time.Weekday{time.Saturday, time.Sunday},
+		Holidays:        []time.Time{time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC)},
+		Horizon:         5,
+	}
+
+	// Christmas eve, past the ready time
+	now := time.Date(2020, 12, 24, 12, 0, 0, 0, losAngeles)
+	windows, err := policy.Windows(models.Address{StateOrProvinceCode: "CA", PostalCode: "90401", CountryCode: "US"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) != 2 ||
+		!windows[0].ReadyTime.Equal(time.Date(2020, 12, 28, 9, 0, 0, 0, losAngeles)) ||
+		!windows[0].CloseTime.Equal(time.Date(2020, 12, 28, 17, 0, 0, 0, losAngeles)) ||
+		!windows[1].ReadyTime.Equal(time.Date(2020, 12, 29, 9, 0, 0, 0, losAngeles)) {
+		t.Fatal("should skip today, holidays and blocked weekdays", windows)
+	}
+
+	// The day clocks move forward, in another postal code
+	now = time.Date(2021, 3, 14, 0, 0, 0, 0, losAngeles)
+	address := models.Address{StateOrProvinceCode: "CA", PostalCode: "94103", CountryCode: "US"}
+	windows, err = DefaultPickupPolicy.Windows(address, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(windows) == 0 || windows[0].ReadyTime.Hour() != 10 || windows[0].ReadyTime.Minute() != 45 {
+		t.Fatal("ready time should be the same on DST days", windows)
+	}
+
+	france := models.Address{CountryCode: "FR", PostalCode: "75001"}
+	windows, err = DefaultPickupPolicy.Windows(france, now)
+	if err != nil || len(windows) == 0 || windows[0].ReadyTime.Location().String() != "America/Los_Angeles" {
+		t.Fatal("addresses without a time zone should default to Los Angeles", windows, err)
+	}
+	policy.TimeZoneResolver = PostalCodeTimeZoneResolver{}
+	if _, err := policy.Windows(france, now); err == nil {
+		t.Fatal("addresses without a time zone should fail without a default time zone")
+	}
+}
+
+func TestBusinessDaysUntil(t *testing.T) {
+	friday := time.Date(2020, 12, 18, 10, 45, 0, 0, time.UTC)
+	tests := []struct {
+		to   time.Time
+		days int
+	}{
+		{friday, 0},
+		{friday.AddDate(0, 0, 2), 0},
+		{friday.AddDate(0, 0, 3), 1},
+		{friday.AddDate(0, 0, 6), 4},
+	}
+	for _, test := range tests {
+		if days := businessDaysUntil(friday, test.to); days != test.days {
+			t.Fatal("expected", test.days, "business days until", test.to, "got", days)
+		}
+	}
+}
+
+func TestFilterAvailableWindows(t *testing.T) {
+	losAngeles, _ := time.LoadLocation("America/Los_Angeles")
+	window := func(day int, readyHour, closeHour int) models.PickupTimeWindow {
+		return models.PickupTimeWindow{
+			ReadyTime: time.Date(2020, 12, day, readyHour, 0, 0, 0, losAngeles),
+			CloseTime: time.Date(2020, 12, day, closeHour, 0, 0, 0, losAngeles),
+		}
+	}
+	windows := []models.PickupTimeWindow{window(21, 11, 18), window(22, 11, 12), window(23, 11, 18), window(24, 11, 18)}
+	options := []models.PickupScheduleOption{
+		{PickupDate: "2020-12-21", CutOffTime: "15:00:00", AccessTime: "PT2H0M"},
+		{PickupDate: "2020-12-22", CutOffTime: "15:00:00", AccessTime: "PT2H0M"},
+		{PickupDate: "2020-12-23", CutOffTime: "15:00:00", AccessTime: "PT2H0M"},
+	}
+
+	// Past the cut off of the first day
+	now := time.Date(2020, 12, 21, 16, 0, 0, 0, losAngeles)
+	available := filterAvailableWindows(windows, options, now)
+	if len(available) != 1 || available[0] != windows[2] {
+		t.Fatal("should skip days past their cut off, too short for the access time or not available", available)
+	}
+}
@@ -0,0 +1,240 @@
+package fedex
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// TimeZoneResolver returns the time zone of an address
+type TimeZoneResolver interface {
+	TimeZone(address models.Address) (*time.Location, error)
+}
+
+// TimeZoneResolverFunc is a func used as a TimeZoneResolver
+type TimeZoneResolverFunc func(address models.Address) (*time.Location, error)
+
+// TimeZone calls f
+func (f TimeZoneResolverFunc) TimeZone(address models.Address) (*time.Location, error) {
+	return f(address)
+}
+
+// PostalCodeTimeZoneResolver resolves the time zones of US and Canadian
+// addresses from their state or province, and from their postal code in
+// states and provinces spanning several time zones. Postal codes are only
+// looked at by prefix, so addresses right on a time zone line can be wrong.
+// Other addresses are in DefaultTimeZone.
+type PostalCodeTimeZoneResolver struct {
+	// DefaultTimeZone is the time zone database name, like
+	// America/Los_Angeles, of addresses in other countries or with an unknown
+	// state. They fail to resolve when it's empty.
+	DefaultTimeZone string
+}
+
+// DefaultTimeZoneResolver is the TimeZoneResolver of a PickupPolicy without
+// one. Like pickups always did, it falls back to the Los Angeles time zone.
+var DefaultTimeZoneResolver TimeZoneResolver = PostalCodeTimeZoneResolver{DefaultTimeZone: "America/Los_Angeles"}
+
+// usStateTimeZones are the time zones of most of each state
+var usStateTimeZones = map[string]string{
+	"AK": "America/Anchorage",
+	"AL": "America/Chicago",
+	"AR": "America/Chicago",
+	"AZ": "America/Phoenix",
+	"CA": "America/Los_Angeles",
+	"CO": "America/Denver",
+	"CT": "America/New_York",
+	"DC": "America/New_York",
+	"DE": "America/New_York",
+	"FL": "America/New_York",
+	"GA": "America/New_York",
+	"GU": "Pacific/Guam",
+	"HI": "Pacific/Honolulu",
+	"IA": "America/Chicago",
+	"ID": "America/Boise",
+	"IL": "America/Chicago",
+	"IN": "America/Indiana/Indianapolis",
+	"KS": "America/Chicago",
+	"KY": "America/New_York",
+	"LA": "America/Chicago",
+	"MA": "America/New_York",
+	"MD": "America/New_York",
+	"ME": "America/New_York",
+	"MI": "America/Detroit",
+	"MN": "America/Chicago",
+	"MO": "America/Chicago",
+	"MS": "America/Chicago",
+	"MT": "America/Denver",
+	"NC": "America/New_York",
+	"ND": "America/Chicago",
+	"NE": "America/Chicago",
+	"NH": "America/New_York",
+	"NJ": "America/New_York",
+	"NM": "America/Denver",
+	"NV": "America/Los_Angeles",
+	"NY": "America/New_York",
+	"OH": "America/New_York",
+	"OK": "America/Chicago",
+	"OR": "America/Los_Angeles",
+	"PA": "America/New_York",
+	"PR": "America/Puerto_Rico",
+	"RI": "America/New_York",
+	"SC": "America/New_York",
+	"SD": "America/Chicago",
+	"TN": "America/Chicago",
+	"TX": "America/Chicago",
+	"UT": "America/Denver",
+	"VA": "America/New_York",
+	"VI": "America/St_Thomas",
+	"VT": "America/New_York",
+	"WA": "America/Los_Angeles",
+	"WI": "America/Chicago",
+	"WV": "America/New_York",
+	"WY": "America/Denver",
+}
+
+// usZIP3TimeZones are the time zones of the ZIP code prefixes that aren't in
+// the time zone of their state
+var usZIP3TimeZones = map[string]string{
+	// Florida panhandle
+	"324": "America/Chicago",
+	"325": "America/Chicago",
+	// Northern Idaho
+	"835": "America/Los_Angeles",
+	"838": "America/Los_Angeles",
+	// Northwest and southwest Indiana
+	"463": "America/Chicago",
+	"464": "America/Chicago",
+	"476": "America/Chicago",
+	"477": "America/Chicago",
+	// Western Kentucky
+	"420": "America/Chicago",
+	"421": "America/Chicago",
+	"422": "America/Chicago",
+	"423": "America/Chicago",
+	"424": "America/Chicago",
+	// Western Nebraska
+	"693": "America/Denver",
+	// Southwest North Dakota
+	"586": "America/Denver",
+	// Eastern Oregon
+	"979": "America/Boise",
+	// Western South Dakota
+	"577": "America/Denver",
+	// East Tennessee
+	"373": "America/New_York",
+	"374": "America/New_York",
+	"376": "America/New_York",
+	"377": "America/New_York",
+	"378": "America/New_York",
+	"379": "America/New_York",
+	// El Paso
+	"798": "America/Denver",
+	"799": "America/Denver",
+	"885": "America/Denver",
+}
+
+// caProvinceTimeZones are the time zones of most of each province
+var caProvinceTimeZones = map[string]string{
+	"AB": "America/Edmonton",
+	"BC": "America/Vancouver",
+	"MB": "America/Winnipeg",
+	"NB": "America/Moncton",
+	"NL": "America/St_Johns",
+	"NS": "America/Halifax",
+	"NT": "America/Yellowknife",
+	"NU": "America/Iqaluit",
+	"ON": "America/Toronto",
+	"PE": "America/Halifax",
+	"QC": "America/Toronto",
+	"SK": "America/Regina",
+	"YT": "America/Whitehorse",
+}
+
+// caFSATimeZones are the time zones of the forward sortation areas, the
+// first three characters of postal codes, that aren't in the time zone of
+// their province
+var caFSATimeZones = map[string]string{
+	// Labrador
+	"A0P": "America/Goose_Bay",
+	"A0R": "America/Goose_Bay",
+	"A2V": "America/Goose_Bay",
+	// Magdalen Islands
+	"G4T": "America/Halifax",
+	// Northwestern Ontario
+	"P8N": "America/Winnipeg",
+	"P8T": "America/Winnipeg",
+	"P9A": "America/Winnipeg",
+	"P9N": "America/Winnipeg",
+	// Lloydminster
+	"S9V": "America/Edmonton",
+	// Northeastern and southeastern British Columbia
+	"V1C": "America/Edmonton",
+	"V1G": "America/Dawson_Creek",
+	"V1J": "America/Dawson_Creek",
+	// Central and western Nunavut
+	"X0B": "America/Cambridge_Bay",
+	"X0C": "America/Rankin_Inlet",
+}
+
+// TimeZone returns the time zone of a US or Canadian address, or else
+// DefaultTimeZone
+func (r PostalCodeTimeZoneResolver) TimeZone(address models.Address) (*time.Location, error) {
+	postalCode := strings.ToUpper(strings.ReplaceAll(address.PostalCode, " ", ""))
+	state := strings.ToUpper(address.StateOrProvinceCode)
+
+	tzDatabaseName := ""
+	switch strings.ToUpper(address.CountryCode) {
+	case "US", "":
+		if len(postalCode) >= 3 {
+			tzDatabaseName = usZIP3TimeZones[postalCode[:3]]
+		}
+		if tzDatabaseName == "" {
+			tzDatabaseName = usStateTimeZones[state]
+		}
+	case "CA":
+		if len(postalCode) >= 3 {
+			tzDatabaseName = caFSATimeZones[postalCode[:3]]
+		}
+		if provinces := models.CAPostalCodeProvinces(postalCode); state == "" && len(provinces) > 0 {
+			state = provinces[0]
+		}
+		if tzDatabaseName == "" {
+			tzDatabaseName = caProvinceTimeZones[state]
+		}
+	case "PR", "VI", "GU":
+		tzDatabaseName = usStateTimeZones[strings.ToUpper(address.CountryCode)]
+	}
+
+	if tzDatabaseName == "" {
+		tzDatabaseName = r.DefaultTimeZone
+	}
+	if tzDatabaseName == "" {
+		return nil, fmt.Errorf("no time zone for state %s of country %s", address.StateOrProvinceCode, address.CountryCode)
+	}
+	return loadLocation(tzDatabaseName)
+}
+
+var (
+	locationsMu sync.Mutex
+	locations   = map[string]*time.Location{}
+)
+
+// loadLocation is time.LoadLocation, but only reads each time zone once
+func loadLocation(name string) (*time.Location, error) {
+	locationsMu.Lock()
+	defer locationsMu.Unlock()
+
+	if location, ok := locations[name]; ok {
+		return location, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("load location from time zone %s: %w", name, err)
+	}
+	locations[name] = location
+	return location, nil
+}
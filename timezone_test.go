@@ -0,0 +1,32 @@
+package fedex
+
+import (
+	"testing"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+func TestPostalCodeTimeZoneResolver(t *testing.T) {
+	tests := []struct {
+		address  models.Address
+		timeZone string
+	}{
+		{models.Address{StateOrProvinceCode: "CA", PostalCode: "90401", CountryCode: "US"}, "America/Los_Angeles"},
+		{models.Address{StateOrProvinceCode: "TX", PostalCode: "79901", CountryCode: "US"}, "America/Denver"},
+		{models.Address{StateOrProvinceCode: "TX", PostalCode: "75201", CountryCode: "US"}, "America/Chicago"},
+		{models.Address{StateOrProvinceCode: "IN", PostalCode: "46204", CountryCode: "US"}, "America/Indiana/Indianapolis"},
+		{models.Address{StateOrProvinceCode: "IN", PostalCode: "46402-1234", CountryCode: "US"}, "America/Chicago"},
+		{models.Address{StateOrProvinceCode: "AZ", PostalCode: "85004", CountryCode: "US"}, "America/Phoenix"},
+		{models.Address{StateOrProvinceCode: "ON", PostalCode: "M5V 2T6", CountryCode: "CA"}, "America/Toronto"},
+		{models.Address{StateOrProvinceCode: "ON", PostalCode: "P9N 1A1", CountryCode: "CA"}, "America/Winnipeg"},
+		{models.Address{PostalCode: "T2P 1J9", CountryCode: "CA"}, "America/Edmonton"},
+		{models.Address{CountryCode: "US"}, "America/Los_Angeles"},
+		{models.Address{PostalCode: "75008", CountryCode: "FR"}, "America/Los_Angeles"},
+	}
+	for _, test := range tests {
+		location, err := DefaultTimeZoneResolver.TimeZone(test.address)
+		if err != nil || location.String() != test.timeZone {
+			t.Fatal(test.address.PostalCode, "should be in", test.timeZone, "got", location, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package tracking
+
+import (
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// Schedule says how often shipments are polled
+type Schedule struct {
+	// OutForDelivery is the interval for packages out for delivery
+	OutForDelivery time.Duration
+	// Active is the interval for packages that moved recently
+	Active time.Duration
+	// Idle is the interval for packages that haven't moved for IdleAfter,
+	// including labels FedEx doesn't know about yet
+	Idle      time.Duration
+	IdleAfter time.Duration
+}
+
+// DefaultSchedule is the Schedule of a new Watcher
+var DefaultSchedule = Schedule{
+	OutForDelivery: 15 * time.Minute,
+	Active:         time.Hour,
+	Idle:           6 * time.Hour,
+	IdleAfter:      24 * time.Hour,
+}
+
+// interval returns how long to wait before polling shipment again
+func (s Schedule) interval(shipment Shipment, now time.Time) time.Duration {
+	switch {
+	case shipment.Status == models.TrackingStatusOutForDelivery:
+		return s.OutForDelivery
+	case shipment.LastScan.IsZero() || now.Sub(shipment.LastScan) >= s.IdleAfter:
+		return s.Idle
+	default:
+		return s.Active
+	}
+}
@@ -0,0 +1,101 @@
+package tracking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/happyreturns/fedex/models"
+)
+
+// Shipment is a watched package and what the Watcher last saw of it
+type Shipment struct {
+	TrackingNumber string            `json:"trackingNumber"`
+	CarrierCode    string            `json:"carrierCode"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+
+	Status            models.TrackingStatus `json:"status,omitempty"`
+	EstimatedDelivery time.Time             `json:"estimatedDelivery,omitempty"`
+	// LastScan is the time of the latest scan seen
+	LastScan time.Time `json:"lastScan,omitempty"`
+	// Scans identify the scans seen, see scanKey
+	Scans []string `json:"scans,omitempty"`
+
+	WatchedSince time.Time `json:"watchedSince"`
+	LastPoll     time.Time `json:"lastPoll,omitempty"`
+	NextPoll     time.Time `json:"nextPoll"`
+}
+
+func (s Shipment) hasScan(key string) bool {
+	for _, scan := range s.Scans {
+		if scan == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Store persists watched shipments, so watching survives restarts
+type Store interface {
+	// Save creates or replaces the shipment with the same tracking number
+	Save(ctx context.Context, shipment Shipment) error
+	// Get returns the shipment with trackingNumber, and false if there is none
+	Get(ctx context.Context, trackingNumber string) (Shipment, bool, error)
+	Delete(ctx context.Context, trackingNumber string) error
+	// Due returns the shipments to poll at now, i.e. with NextPoll not after it
+	Due(ctx context.Context, now time.Time) ([]Shipment, error)
+}
+
+// MemoryStore is a Store keeping shipments in memory. Its zero value is an
+// empty store.
+type MemoryStore struct {
+	mu        sync.Mutex
+	shipments map[string]Shipment
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{shipments: map[string]Shipment{}}
+}
+
+func (m *MemoryStore) Save(ctx context.Context, shipment Shipment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Reading and deleting from a nil map work, only Save needs it
+	if m.shipments == nil {
+		m.shipments = map[string]Shipment{}
+	}
+	m.shipments[shipment.TrackingNumber] = shipment
+	return nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, trackingNumber string) (Shipment, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shipment, ok := m.shipments[trackingNumber]
+	return shipment, ok, nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, trackingNumber string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.shipments, trackingNumber)
+	return nil
+}
+
+func (m *MemoryStore) Due(ctx context.Context, now time.Time) ([]Shipment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	due := []Shipment{}
+	for _, shipment := range m.shipments {
+		if !shipment.NextPoll.After(now) {
+			due = append(due, shipment)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextPoll.Before(due[j].NextPoll)
+	})
+	return due, nil
+}
@@ -0,0 +1,33 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestZeroMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	store := &MemoryStore{}
+
+	if _, ok, err := store.Get(ctx, "794000000001"); ok || err != nil {
+		t.Fatal("empty store should not have shipments", err)
+	}
+	if due, err := store.Due(ctx, now); len(due) != 0 || err != nil {
+		t.Fatal("empty store should not have due shipments", due, err)
+	}
+	if err := store.Delete(ctx, "794000000001"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save(ctx, Shipment{TrackingNumber: "794000000001", NextPoll: now}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := store.Get(ctx, "794000000001"); !ok {
+		t.Fatal("saved shipment should be found")
+	}
+	if due, _ := store.Due(ctx, now); len(due) != 1 {
+		t.Fatal("saved shipment should be due", due)
+	}
+}
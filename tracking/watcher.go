@@ -0,0 +1,333 @@
+// Package tracking watches FedEx shipments, polling them on an adaptive
+// schedule and emitting events when they change.
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/happyreturns/fedex/api"
+	"github.com/happyreturns/fedex/models"
+)
+
+// Tracker tracks packages in batches. api.API and fedex.Fedex are Trackers.
+type Tracker interface {
+	TrackManyContext(ctx context.Context, carrierCode string, trackingNumbers []string) []api.TrackResult
+}
+
+// EventType is the kind of change of an Event
+type EventType string
+
+// Event types
+const (
+	// EventNewScan is a scan that wasn't seen before
+	EventNewScan EventType = "NEW_SCAN"
+	// EventStatusChange is a change of the normalized status, including the
+	// first status seen
+	EventStatusChange EventType = "STATUS_CHANGE"
+	// EventETAChange is a change of the estimated delivery
+	EventETAChange EventType = "ETA_CHANGE"
+	// EventDelivered is emitted once the package is delivered, after which it
+	// isn't watched anymore
+	EventDelivered EventType = "DELIVERED"
+	// EventException is emitted when the package gets an exception status
+	EventException EventType = "EXCEPTION"
+	// EventExpired is emitted when the package is no longer watched because
+	// its TTL passed
+	EventExpired EventType = "EXPIRED"
+)
+
+// Event is a change of a watched shipment
+type Event struct {
+	Type EventType
+	// Shipment is the shipment after the change
+	Shipment Shipment
+	// Scan is the new scan of EventNewScan
+	Scan *models.Event
+	// PreviousStatus is the status before EventStatusChange
+	PreviousStatus models.TrackingStatus
+	// PreviousEstimatedDelivery is the estimated delivery before
+	// EventETAChange, zero if there was none
+	PreviousEstimatedDelivery time.Time
+}
+
+// Watcher polls watched shipments and emits their changes. NewWatcher sets
+// its defaults, but a zero Watcher with a Tracker and a Store works too.
+type Watcher struct {
+	Tracker  Tracker
+	Store    Store
+	Schedule Schedule
+	// TTL is how long a shipment is watched without being delivered. Zero
+	// means forever.
+	TTL time.Duration
+	// PollInterval is how often Run checks for shipments due to be polled,
+	// every minute when zero
+	PollInterval time.Duration
+	// OnEvent, when set, receives the events instead of the Events channel
+	OnEvent func(Event)
+	// Logger receives failures to track. api.DefaultLogger is used when nil.
+	Logger api.Logger
+
+	eventsOnce sync.Once
+	events     chan Event
+	now        func() time.Time
+}
+
+// DefaultTTL is the TTL of a new Watcher
+const DefaultTTL = 30 * 24 * time.Hour
+
+// NewWatcher returns a Watcher tracking with tracker and persisting shipments
+// in store
+func NewWatcher(tracker Tracker, store Store) *Watcher {
+	return &Watcher{
+		Tracker:      tracker,
+		Store:        store,
+		Schedule:     DefaultSchedule,
+		TTL:          DefaultTTL,
+		PollInterval: time.Minute,
+	}
+}
+
+// Events returns the channel events are sent on when OnEvent isn't set. It
+// must be read, or polling blocks.
+func (w *Watcher) Events() <-chan Event {
+	return w.eventsChan()
+}
+
+func (w *Watcher) eventsChan() chan Event {
+	w.eventsOnce.Do(func() {
+		w.events = make(chan Event, 100)
+	})
+	return w.events
+}
+
+func (w *Watcher) clock() time.Time {
+	if w.now == nil {
+		return time.Now()
+	}
+	return w.now()
+}
+
+// Watch starts watching trackingNumber. It's polled on the next Poll.
+// Watching a shipment already watched only replaces its metadata.
+func (w *Watcher) Watch(ctx context.Context, carrierCode, trackingNumber string, metadata map[string]string) error {
+	shipment, ok, err := w.Store.Get(ctx, trackingNumber)
+	if err != nil {
+		return fmt.Errorf("get shipment: %w", err)
+	}
+	if !ok {
+		now := w.clock()
+		shipment = Shipment{
+			TrackingNumber: trackingNumber,
+			CarrierCode:    carrierCode,
+			WatchedSince:   now,
+			NextPoll:       now,
+		}
+	}
+	shipment.Metadata = metadata
+
+	if err := w.Store.Save(ctx, shipment); err != nil {
+		return fmt.Errorf("save shipment: %w", err)
+	}
+	return nil
+}
+
+// Unwatch stops watching trackingNumber
+func (w *Watcher) Unwatch(ctx context.Context, trackingNumber string) error {
+	if err := w.Store.Delete(ctx, trackingNumber); err != nil {
+		return fmt.Errorf("delete shipment: %w", err)
+	}
+	return nil
+}
+
+// Run polls until ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	pollInterval := w.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Minute
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil && ctx.Err() == nil {
+			w.logger().Error("poll", api.Fields{"err": err})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll tracks the shipments that are due, and emits their changes. Failures
+// to track a shipment are logged and it's polled again later, errors are only
+// returned for the store.
+func (w *Watcher) Poll(ctx context.Context) error {
+	now := w.clock()
+	due, err := w.Store.Due(ctx, now)
+	if err != nil {
+		return fmt.Errorf("get due shipments: %w", err)
+	}
+
+	byCarrierCode := map[string][]Shipment{}
+	for _, shipment := range due {
+		byCarrierCode[shipment.CarrierCode] = append(byCarrierCode[shipment.CarrierCode], shipment)
+	}
+
+	for carrierCode, shipments := range byCarrierCode {
+		trackingNumbers := make([]string, len(shipments))
+		for idx, shipment := range shipments {
+			trackingNumbers[idx] = shipment.TrackingNumber
+		}
+
+		results := w.Tracker.TrackManyContext(ctx, carrierCode, trackingNumbers)
+		for idx, result := range results {
+			if err := w.update(ctx, shipments[idx], result, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// update applies result to shipment, emits the changes and saves or deletes
+// it
+func (w *Watcher) update(ctx context.Context, shipment Shipment, result api.TrackResult, now time.Time) error {
+	shipment.LastPoll = now
+
+	var (
+		events []Event
+		done   bool
+	)
+	switch {
+	case result.Err == nil:
+		events, done = diff(&shipment, result.Reply)
+	case errors.Is(result.Err, models.ErrTrackingNotFound):
+		// Labels aren't found until FedEx gets the shipment information
+	default:
+		w.logger().Error("track shipment", api.Fields{
+			"trackingNumber": shipment.TrackingNumber,
+			"err":            result.Err,
+		})
+	}
+
+	if !done && w.TTL > 0 && now.Sub(shipment.WatchedSince) >= w.TTL {
+		events = append(events, Event{Type: EventExpired})
+		done = true
+	}
+
+	shipment.NextPoll = now.Add(w.Schedule.interval(shipment, now))
+	for _, event := range events {
+		event.Shipment = shipment
+		if err := w.emit(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	if done {
+		if err := w.Store.Delete(ctx, shipment.TrackingNumber); err != nil {
+			return fmt.Errorf("delete shipment: %w", err)
+		}
+		return nil
+	}
+	if err := w.Store.Save(ctx, shipment); err != nil {
+		return fmt.Errorf("save shipment: %w", err)
+	}
+	return nil
+}
+
+// diff updates shipment from reply, and returns the events for what changed
+// and whether the shipment is done being watched
+func diff(shipment *Shipment, reply *models.TrackReply) ([]Event, bool) {
+	trackDetail := reply.PrimaryTrackDetail()
+	if trackDetail == nil {
+		return nil, false
+	}
+
+	events := []Event{}
+	delivered := false
+	for _, scan := range trackDetail.Timeline() {
+		scan := scan
+		if scan.Status() == models.TrackingStatusDelivered {
+			delivered = true
+		}
+		// Scans may share a timestamp or be backfilled before the last one, so
+		// they're told apart by what they are rather than by when
+		key := scanKey(scan)
+		if shipment.hasScan(key) {
+			continue
+		}
+		events = append(events, Event{Type: EventNewScan, Scan: &scan})
+		shipment.Scans = append(shipment.Scans, key)
+		if scanTime := time.Time(scan.Timestamp); scanTime.After(shipment.LastScan) {
+			shipment.LastScan = scanTime
+		}
+	}
+
+	status := trackDetail.Status()
+	if status != shipment.Status {
+		events = append(events, Event{Type: EventStatusChange, PreviousStatus: shipment.Status})
+		if status == models.TrackingStatusException {
+			events = append(events, Event{Type: EventException})
+		}
+		shipment.Status = status
+	}
+
+	estimatedDelivery := time.Time{}
+	if eta := trackDetail.EstimatedDelivery(); eta != nil {
+		estimatedDelivery = *eta
+	}
+	if !estimatedDelivery.Equal(shipment.EstimatedDelivery) {
+		events = append(events, Event{Type: EventETAChange, PreviousEstimatedDelivery: shipment.EstimatedDelivery})
+		shipment.EstimatedDelivery = estimatedDelivery
+	}
+
+	done := status == models.TrackingStatusCancelled ||
+		status == models.TrackingStatusDelivered ||
+		(status == models.TrackingStatusReturnedToShipper && delivered)
+	if done && status != models.TrackingStatusCancelled {
+		events = append(events, Event{Type: EventDelivered})
+	}
+	return events, done
+}
+
+func (w *Watcher) emit(ctx context.Context, event Event) error {
+	if w.OnEvent != nil {
+		w.OnEvent(event)
+		return nil
+	}
+
+	select {
+	case w.eventsChan() <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// scanKey identifies scan by its time, type and location
+func scanKey(scan models.Event) string {
+	address := scan.Address
+	return strings.Join([]string{
+		time.Time(scan.Timestamp).UTC().Format(time.RFC3339),
+		scan.EventType,
+		address.City,
+		address.StateOrProvinceCode,
+		address.PostalCode,
+		address.CountryCode,
+	}, "|")
+}
+
+func (w *Watcher) logger() api.Logger {
+	if w.Logger == nil {
+		return api.DefaultLogger
+	}
+	return w.Logger
+}
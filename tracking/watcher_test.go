@@ -0,0 +1,277 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/happyreturns/fedex/api"
+	"github.com/happyreturns/fedex/fedextest"
+	"github.com/happyreturns/fedex/models"
+)
+
+func newTestWatcher(server *fedextest.Server, now *time.Time) (*Watcher, *[]Event) {
+	a := api.API{
+		FedExURL:    server.URL,
+		Logger:      api.NopLogger{},
+		RetryPolicy: &api.RetryPolicy{MaxAttempts: 1},
+	}
+
+	events := &[]Event{}
+	w := NewWatcher(a, NewMemoryStore())
+	w.Logger = api.NopLogger{}
+	w.OnEvent = func(event Event) { *events = append(*events, event) }
+	w.now = func() time.Time { return *now }
+	return w, events
+}
+
+func eventTypes(events []Event) []EventType {
+	types := []EventType{}
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func expectEvents(t *testing.T, events *[]Event, expected ...EventType) {
+	t.Helper()
+	types := eventTypes(*events)
+	if len(types) != len(expected) {
+		t.Fatal("expected events", expected, "got", types)
+	}
+	for idx := range types {
+		if types[idx] != expected[idx] {
+			t.Fatal("expected events", expected, "got", types)
+		}
+	}
+	*events = nil
+}
+
+func TestWatcher(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	now := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	w, events := newTestWatcher(server, &now)
+
+	if err := w.Watch(ctx, "FDXG", "794000000001", map[string]string{"returnID": "42"}); err != nil {
+		t.Fatal(err)
+	}
+
+	labelCreated := fedextest.TrackingEvent{Timestamp: now.Add(-time.Hour), EventType: "OC"}
+	server.SetTracking("794000000001", fedextest.Tracking{
+		CarrierCode: "FDXG",
+		Events:      []fedextest.TrackingEvent{labelCreated},
+	})
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, EventNewScan, EventStatusChange)
+
+	// Not due yet
+	now = now.Add(time.Minute)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events)
+
+	pickedUp := fedextest.TrackingEvent{Timestamp: now, EventType: "PU"}
+	eta := time.Date(2020, 10, 3, 17, 0, 0, 0, time.UTC)
+	server.SetTracking("794000000001", fedextest.Tracking{
+		CarrierCode:       "FDXG",
+		EstimatedDelivery: eta,
+		Events:            []fedextest.TrackingEvent{pickedUp, labelCreated},
+	})
+	now = now.Add(time.Hour)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(*events) == 0 || (*events)[0].Shipment.Metadata["returnID"] != "42" {
+		t.Fatal("events should have the metadata of the shipment")
+	}
+	expectEvents(t, events, EventNewScan, EventStatusChange, EventETAChange)
+
+	shipment, ok, _ := w.Store.Get(ctx, "794000000001")
+	if !ok || !shipment.NextPoll.Equal(now.Add(DefaultSchedule.Active)) {
+		t.Fatal("moving packages should be polled every hour", shipment.NextPoll)
+	}
+
+	outForDelivery := fedextest.TrackingEvent{Timestamp: now, EventType: "OD"}
+	server.SetTracking("794000000001", fedextest.Tracking{
+		CarrierCode:       "FDXG",
+		EstimatedDelivery: eta,
+		Events:            []fedextest.TrackingEvent{outForDelivery, pickedUp, labelCreated},
+	})
+	now = now.Add(time.Hour)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, EventNewScan, EventStatusChange)
+
+	shipment, _, _ = w.Store.Get(ctx, "794000000001")
+	if !shipment.NextPoll.Equal(now.Add(DefaultSchedule.OutForDelivery)) {
+		t.Fatal("packages out for delivery should be polled more often", shipment.NextPoll)
+	}
+
+	delivered := fedextest.TrackingEvent{Timestamp: now, EventType: "DL"}
+	server.SetTracking("794000000001", fedextest.Tracking{
+		CarrierCode:    "FDXG",
+		ActualDelivery: now,
+		Events:         []fedextest.TrackingEvent{delivered, outForDelivery, pickedUp, labelCreated},
+	})
+	now = now.Add(DefaultSchedule.OutForDelivery)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, EventNewScan, EventStatusChange, EventETAChange, EventDelivered)
+
+	if _, ok, _ := w.Store.Get(ctx, "794000000001"); ok {
+		t.Fatal("delivered packages should not be watched anymore")
+	}
+}
+
+func TestWatcherTTL(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	now := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	w, events := newTestWatcher(server, &now)
+	w.TTL = 48 * time.Hour
+
+	if err := w.Watch(ctx, "FDXG", "794000000002", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Not found yet, so idle
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events)
+	shipment, _, _ := w.Store.Get(ctx, "794000000002")
+	if !shipment.NextPoll.Equal(now.Add(DefaultSchedule.Idle)) {
+		t.Fatal("packages not found should be polled less often", shipment.NextPoll)
+	}
+
+	now = now.Add(w.TTL)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, EventExpired)
+	if _, ok, _ := w.Store.Get(ctx, "794000000002"); ok {
+		t.Fatal("expired packages should not be watched anymore")
+	}
+}
+
+func TestWatcherEventsChannel(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	now := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	w, _ := newTestWatcher(server, &now)
+	w.OnEvent = nil
+
+	server.SetTracking("794000000003", fedextest.Tracking{
+		CarrierCode: "FDXG",
+		Events:      []fedextest.TrackingEvent{{Timestamp: now, EventType: "SE"}},
+	})
+	if err := w.Watch(ctx, "FDXG", "794000000003", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []EventType{EventNewScan, EventStatusChange, EventException}
+	for _, eventType := range expected {
+		event := <-w.Events()
+		if event.Type != eventType {
+			t.Fatal("expected", eventType, "got", event.Type)
+		}
+	}
+	if len(w.events) != 0 {
+		t.Fatal("should not have more events")
+	}
+	shipment, _, _ := w.Store.Get(ctx, "794000000003")
+	if shipment.Status != models.TrackingStatusException {
+		t.Fatal("should be an exception, got", shipment.Status)
+	}
+}
+
+func TestWatcherSameTimeAndBackfilledScans(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	now := time.Date(2020, 10, 1, 8, 0, 0, 0, time.UTC)
+	w, events := newTestWatcher(server, &now)
+
+	arrived := fedextest.TrackingEvent{Timestamp: now, EventType: "AR", City: "LOS ANGELES", StateOrProvinceCode: "CA"}
+	server.SetTracking("794000000004", fedextest.Tracking{
+		CarrierCode: "FDXG",
+		Events:      []fedextest.TrackingEvent{arrived},
+	})
+	if err := w.Watch(ctx, "FDXG", "794000000004", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events, EventNewScan, EventStatusChange)
+
+	// A departure at the same time, and a pickup FedEx backfilled before both
+	departed := fedextest.TrackingEvent{Timestamp: now, EventType: "DP", City: "LOS ANGELES", StateOrProvinceCode: "CA"}
+	pickedUp := fedextest.TrackingEvent{Timestamp: now.Add(-time.Hour), EventType: "PU", City: "SANTA MONICA", StateOrProvinceCode: "CA"}
+	server.SetTracking("794000000004", fedextest.Tracking{
+		CarrierCode: "FDXG",
+		Events:      []fedextest.TrackingEvent{departed, arrived, pickedUp},
+	})
+	now = now.Add(DefaultSchedule.Active)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	newScans := []string{}
+	for _, event := range *events {
+		if event.Type == EventNewScan {
+			newScans = append(newScans, event.Scan.EventType)
+		}
+	}
+	if len(newScans) != 2 || newScans[0] != "PU" || newScans[1] != "DP" {
+		t.Fatal("expected the backfilled and same time scans, got", newScans)
+	}
+	*events = nil
+
+	now = now.Add(DefaultSchedule.Active)
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expectEvents(t, events)
+}
+
+func TestZeroWatcher(t *testing.T) {
+	server := fedextest.NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	w := &Watcher{
+		Tracker: api.API{FedExURL: server.URL, Logger: api.NopLogger{}},
+		Store:   NewMemoryStore(),
+		Logger:  api.NopLogger{},
+	}
+
+	server.SetTracking("794000000005", fedextest.Tracking{
+		CarrierCode: "FDXG",
+		Events:      []fedextest.TrackingEvent{{Timestamp: time.Now(), EventType: "OC"}},
+	})
+	if err := w.Watch(ctx, "FDXG", "794000000005", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Poll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if event := <-w.Events(); event.Type != EventNewScan {
+		t.Fatal("expected a new scan, got", event.Type)
+	}
+}